@@ -1,19 +1,31 @@
 package main
 
 import (
+	"authDB/internal/accounts"
+	"authDB/internal/backtest"
+	"authDB/internal/calendar"
+	"authDB/internal/clock"
+	"authDB/internal/deals"
+	"authDB/internal/engine"
 	"authDB/internal/fintech"
-	"authDB/internal/postgres"
+	"authDB/internal/limits"
+	"authDB/internal/market"
+	"authDB/internal/quotes"
 	"authDB/internal/robots"
 	"authDB/internal/sessions"
+	"authDB/internal/strategy"
 	"authDB/internal/user"
 	"authDB/pkg/logger"
-	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"text/template"
 	"time"
@@ -23,6 +35,7 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
 )
 
 // Handler ...
@@ -31,20 +44,34 @@ type Handler struct {
 	repoUser    user.Users
 	repoSession sessions.Sessions
 	repoRobot   robots.Robots
-	streamer    fintech.TradingServiceClient
+	repoDeal    deals.Deals
+	repoState   robots.States
+	repoLimits  limits.Storage
+	repoAccount accounts.Storage
+	repoQuote   quotes.Quotes
+	hub         *market.Hub
+	engine      *engine.Supervisor
+	calendar    *calendar.Calendar
 	templates   map[string]*template.Template
 	wsClients   *wsClients
 }
 
 // NewHandler ...
 func newHandler(newLogger logger.Logger, repoUser user.Users, repoSession sessions.Sessions,
-	repoRobot robots.Robots, streamer fintech.TradingServiceClient, templates map[string]*template.Template, wsClients *wsClients) *Handler {
+	repoRobot robots.Robots, repoDeal deals.Deals, repoState robots.States, repoLimits limits.Storage, repoAccount accounts.Storage, repoQuote quotes.Quotes, hub *market.Hub, engine *engine.Supervisor, calendar *calendar.Calendar, templates map[string]*template.Template, wsClients *wsClients) *Handler {
 	return &Handler{
 		logger:      newLogger,
 		repoUser:    repoUser,
 		repoSession: repoSession,
 		repoRobot:   repoRobot,
-		streamer:    streamer,
+		repoDeal:    repoDeal,
+		repoState:   repoState,
+		repoLimits:  repoLimits,
+		repoAccount: repoAccount,
+		repoQuote:   repoQuote,
+		hub:         hub,
+		engine:      engine,
+		calendar:    calendar,
 		templates:   templates,
 		wsClients:   wsClients,
 	}
@@ -61,6 +88,11 @@ func (h *Handler) Routers(r *chi.Mux) *chi.Mux {
 		r.Post("/signin", h.SignIn)
 		r.Get("/robots", h.FilterRobots)
 		r.HandleFunc("/robots/wsrobots", h.WSRobotsUpdate)
+		r.Get("/market/subscribers", h.MarketSubscribers)
+		r.Get("/market/feeds", h.MarketFeeds)
+		r.Get("/engine/workers", h.EngineWorkers)
+		r.HandleFunc("/tickers/{ticker}/wsprices", h.WSPrices)
+		r.Get("/tickers/{ticker}/quotes", h.GetTickerQuotes)
 		r.Route("/robot", func(r chi.Router) {
 			r.Get("/", h.createRobotHelper)
 			r.Post("/", h.CreateRobot)
@@ -72,12 +104,25 @@ func (h *Handler) Routers(r *chi.Mux) *chi.Mux {
 				r.Put("/favorite", h.FavoriteRobot)
 				r.Put("/activate", h.ActivateRobot)
 				r.Put("/deactivate", h.DeactivateRobot)
+				r.Put("/pause", h.PauseRobot)
+				r.Put("/resume", h.ResumeRobot)
+				r.Get("/deals", h.GetRobotDeals)
+				r.Post("/backtest", h.BacktestRobot)
 			})
 		})
+		r.Route("/admin", func(r chi.Router) {
+			r.Post("/halt", h.Halt)
+			r.Post("/resume", h.ResumeTrading)
+			r.Get("/users/{ID}/limits", h.GetUserLimits)
+			r.Put("/users/{ID}/limits", h.SetUserLimits)
+			r.Post("/users/{ID}/cash", h.TransferCash)
+		})
 		r.Route("/users/{ID}", func(r chi.Router) {
 			r.Get("/", h.GetUser)
 			r.Put("/", h.UpdateUser)
 			r.Get("/robots", h.GetUserRobots)
+			r.Get("/portfolio", h.GetPortfolio)
+			r.Get("/transactions", h.GetTransactions)
 			r.HandleFunc("/wsuserrobot", h.WSUserRobotsUpdate)
 		})
 	})
@@ -86,10 +131,12 @@ func (h *Handler) Routers(r *chi.Mux) *chi.Mux {
 }
 
 const (
-	bufferSize = 1024
-	hour       = 3
-	sec        = 1
-	jsonType   = "application/json"
+	backtestDir = "./data"
+	maxUpload   = 32 << 20
+	csvType     = "text/csv"
+	bufferSize  = 1024
+	sec         = 1
+	jsonType    = "application/json"
 )
 
 // ParseTemplates ...
@@ -106,7 +153,9 @@ func ParseTemplates() map[string]*template.Template {
 	templates["signup"] = template.Must(template.ParseFiles("./template/signup/index.html", "./template/signup/base.html"))
 	templates["createRobot"] = template.Must(template.ParseFiles("./template/createrobot/index.html", "./template/createrobot/base.html"))
 	templates["user_robots"] = template.Must(template.ParseFiles("./template/getuserrobots/index.html", "./template/getuserrobots/base.html"))
+	templates["robot_deals"] = template.Must(template.ParseFiles("./template/getrobotdeals/index.html", "./template/getrobotdeals/base.html"))
 	templates["filter_robots"] = template.Must(template.ParseFiles("./template/getrobots/index.html", "./template/getrobots/base.html"))
+	templates["portfolio"] = template.Must(template.ParseFiles("./template/getportfolio/index.html", "./template/getportfolio/base.html"))
 
 	return templates
 }
@@ -140,6 +189,7 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	u.Birthday = r.FormValue("birthday")
 	u.FirstName = r.FormValue("first_name")
 	u.LastName = r.FormValue("last_name")
+	u.Timezone = r.FormValue("timezone")
 
 	err := user.CheckValidUser(&u)
 	if err != nil {
@@ -375,6 +425,7 @@ func (h *Handler) FilterRobots(w http.ResponseWriter, r *http.Request) { //nolin
 			Filter string
 			// Com    string
 			Robots []*robots.Robot
+			Halted bool
 		}
 
 		// com := fmt.Sprintf("?filter=%s&how=%s", filter, val)
@@ -390,9 +441,13 @@ func (h *Handler) FilterRobots(w http.ResponseWriter, r *http.Request) { //nolin
 				return
 			}
 
+			h.markHalted(robots...)
+			localize(h.tokenLocation(token), robots...)
+
 			data = rbts{
 				Filter: filter,
 				Robots: robots,
+				Halted: h.engine.Halted().Halted,
 				// Com:    com,
 			}
 
@@ -417,7 +472,11 @@ func (h *Handler) FilterRobots(w http.ResponseWriter, r *http.Request) { //nolin
 }
 
 func (h *Handler) createRobotHelper(w http.ResponseWriter, r *http.Request) {
-	h.renderTemplate(w, "createRobot", "")
+	h.renderTemplate(w, "createRobot", struct {
+		Strategies  []string
+		PlanActions []robots.PlanAction
+		Directions  []robots.Direction
+	}{strategy.Names(), robots.PlanActions(), robots.Directions()})
 }
 
 // CreateRobot r.Post("/api/v1/robot", h.CreateRobot)
@@ -425,7 +484,8 @@ func (h *Handler) CreateRobot(w http.ResponseWriter, r *http.Request) {
 	var err error
 
 	rob, err := robots.FormInformationForCreate(r.FormValue("buy_price"),
-		r.FormValue("sell_price"), r.FormValue("plan_yield"), r.FormValue("plan_start"), r.FormValue("plan_end"))
+		r.FormValue("sell_price"), r.FormValue("plan_yield"), r.FormValue("plan_start"), r.FormValue("plan_end"), r.FormValue("quantity"),
+		h.tokenLocation(r.Header.Get("Authorization")))
 	if err != nil {
 		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
 
@@ -439,6 +499,85 @@ func (h *Handler) CreateRobot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rob.Strategy, rob.StrategyParams, err = strategy.Parse(r.FormValue("strategy"), r.FormValue("strategy_params"))
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	rob.StopLoss, err = robots.ParseLevel(r.FormValue("stop_loss"))
+	if err != nil {
+		http.Error(w, "bad stop loss", http.StatusBadRequest)
+
+		return
+	}
+
+	rob.TakeProfit, err = robots.ParseLevel(r.FormValue("take_profit"))
+	if err != nil {
+		http.Error(w, "bad take profit", http.StatusBadRequest)
+
+		return
+	}
+
+	rob.TrailingStop, err = robots.ParseLevel(r.FormValue("trailing_stop"))
+	if err != nil {
+		http.Error(w, "bad trailing stop", http.StatusBadRequest)
+
+		return
+	}
+
+	rob.OnPlanYield, err = robots.ParsePlanAction(r.FormValue("on_plan_yield"))
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	rob.MaxLoss, err = robots.ParseLimit(r.FormValue("max_loss"))
+	if err != nil {
+		http.Error(w, "bad max loss", http.StatusBadRequest)
+
+		return
+	}
+
+	rob.MaxDrawdown, err = robots.ParseLimit(r.FormValue("max_drawdown"))
+	if err != nil {
+		http.Error(w, "bad max drawdown", http.StatusBadRequest)
+
+		return
+	}
+
+	if err = robots.CheckExits(rob); err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	rob.Schedule = strings.TrimSpace(r.FormValue("schedule"))
+	if v := strings.TrimSpace(r.FormValue("schedule_exclude")); v != "" {
+		for _, d := range strings.Split(v, ",") {
+			rob.ScheduleExclude = append(rob.ScheduleExclude, strings.TrimSpace(d))
+		}
+	}
+
+	if err = robots.CheckSchedule(&rob, h.tokenLocation(r.Header.Get("Authorization"))); err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	rob.Direction, err = robots.ParseDirection(r.FormValue("direction"))
+	if err == nil {
+		err = robots.CheckDirection(rob)
+	}
+
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
 	token := r.Header.Get("Authorization")
 
 	userID, err := sessions.DecodeToken(token)
@@ -469,11 +608,21 @@ func (h *Handler) CreateRobot(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+
+		h.warnCalendar(w, &rob)
 	} else {
 		w.WriteHeader(http.StatusForbidden)
 	}
 }
 
+// warnCalendar добавляет к ответу заголовок Warning, если за окно плана биржа тикера не торгует
+func (h *Handler) warnCalendar(w http.ResponseWriter, rob *robots.Robot) {
+	if msg := robots.CalendarWarning(rob, h.calendar); msg != "" {
+		h.logger.Infow("robot plan window has no trading sessions", "robotID", rob.RobotID, "ticker", rob.Ticker)
+		w.Header().Add("Warning", "199 - "+strconv.Quote(msg))
+	}
+}
+
 // DeleteRobot r.Delete("/api/v1/robot/{ID}", h.DeleteRobot)
 func (h *Handler) DeleteRobot(w http.ResponseWriter, r *http.Request) { //nolint
 	token := r.Header.Get("Authorization")
@@ -511,13 +660,19 @@ func (h *Handler) DeleteRobot(w http.ResponseWriter, r *http.Request) { //nolint
 	checkSes := sessions.CheckValidSes(token, ses)
 
 	if checkSes && rb.OwnerUserID == userID {
-		err = h.repoRobot.Delete(robotID)
+		err = robots.CheckTransition(rb.Status, robots.StatusDeleted)
+		if err == nil {
+			err = h.repoRobot.Delete(robotID)
+		}
+
 		if err != nil {
-			h.logger.Debugf("robot was not found %s", err)
-			http.Error(w, "robot was not found", http.StatusNotFound)
+			h.logger.Debugf("failed to delete robot %s", err)
+			http.Error(w, fmt.Sprint(err), robotErrStatus(err))
 
 			return
 		}
+
+		h.engine.Stop(robotID)
 	} else {
 		w.WriteHeader(http.StatusForbidden)
 	}
@@ -606,13 +761,27 @@ func (h *Handler) ActivateRobot(w http.ResponseWriter, r *http.Request) { //noli
 	checkSes := sessions.CheckValidSes(token, ses)
 
 	if checkSes && robot.OwnerUserID == userID {
-		err = h.repoRobot.ActivateRobot(robotID)
+		err = robots.CheckTransition(robot.Status, robots.StatusScheduled)
+		if err == nil {
+			err = h.checkActivateLimits(userID)
+		}
+
+		if err == nil {
+			err = h.checkFunds(robot)
+		}
+
+		if err == nil {
+			err = h.repoRobot.ActivateRobot(robotID)
+		}
+
 		if err != nil {
 			h.logger.Debugf("%s", err)
-			http.Error(w, fmt.Sprint(err), http.StatusNotFound)
+			http.Error(w, fmt.Sprint(err), robotErrStatus(err))
 
 			return
 		}
+
+		h.engine.Reload(robotID)
 	} else {
 		w.WriteHeader(http.StatusForbidden)
 	}
@@ -656,13 +825,75 @@ func (h *Handler) DeactivateRobot(w http.ResponseWriter, r *http.Request) { //no
 	checkSes := sessions.CheckValidSes(token, ses)
 
 	if checkSes && robot.OwnerUserID == userID {
-		err = h.repoRobot.DeactivateRobot(robotID)
+		err = robots.CheckTransition(robot.Status, robots.StatusDraft)
+		if err == nil {
+			err = h.repoRobot.DeactivateRobot(robotID, "deactivated by user")
+		}
+
+		if err != nil {
+			h.logger.Debugf("%s", err)
+			http.Error(w, fmt.Sprint(err), robotErrStatus(err))
+
+			return
+		}
+
+		h.engine.Reload(robotID)
+	} else {
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
+
+// PauseRobot r.Put("/api/v1/robot/{ID}/pause", h.PauseRobot)
+func (h *Handler) PauseRobot(w http.ResponseWriter, r *http.Request) {
+	h.moveRobot(w, r, robots.StatusPaused, "paused by user")
+}
+
+// ResumeRobot r.Put("/api/v1/robot/{ID}/resume", h.ResumeRobot)
+func (h *Handler) ResumeRobot(w http.ResponseWriter, r *http.Request) {
+	h.moveRobot(w, r, robots.StatusRunning, "resumed by user")
+}
+
+// moveRobot переводит робота владельца в стадию to и сразу сообщает об этом движку
+func (h *Handler) moveRobot(w http.ResponseWriter, r *http.Request, to robots.Status, reason string) {
+	token := r.Header.Get("Authorization")
+
+	robotID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad param id", http.StatusBadRequest)
+
+		return
+	}
+
+	ses, err := h.repoSession.FindByToken(token)
+	if err != nil {
+		h.logger.Debugf("session was not found %s", err)
+		http.Error(w, "session was not found", http.StatusBadRequest)
+
+		return
+	}
+
+	robot, err := h.repoRobot.GetRobot(robotID)
+	if err != nil {
+		h.logger.Debugf("robot was not found %s", err)
+		http.Error(w, "robot was not found", http.StatusNotFound)
+
+		return
+	}
+
+	if sessions.CheckValidSes(token, ses) && robot.OwnerUserID == ses.UserID {
+		err = robots.CheckTransition(robot.Status, to)
+		if err == nil {
+			err = h.repoRobot.SetStatus(robotID, to, reason)
+		}
+
 		if err != nil {
 			h.logger.Debugf("%s", err)
-			http.Error(w, fmt.Sprint(err), http.StatusNotFound)
+			http.Error(w, fmt.Sprint(err), robotErrStatus(err))
 
 			return
 		}
+
+		h.engine.Reload(robotID)
 	} else {
 		w.WriteHeader(http.StatusForbidden)
 	}
@@ -694,6 +925,7 @@ func (h *Handler) GetUserRobots(w http.ResponseWriter, r *http.Request) {
 		type rbts struct {
 			UserID int
 			Robots []*robots.Robot
+			Halted bool
 		}
 
 		robots, err := h.repoRobot.GetAllUserRobots(userID)
@@ -704,9 +936,13 @@ func (h *Handler) GetUserRobots(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		h.markHalted(robots...)
+		localize(h.tokenLocation(token), robots...)
+
 		data := rbts{
 			UserID: userID,
 			Robots: robots,
+			Halted: h.engine.Halted().Halted,
 		}
 
 		if content == jsonType {
@@ -777,25 +1013,80 @@ func (h *Handler) UpdateRobot(w http.ResponseWriter, r *http.Request) { //nolint
 			return
 		}
 
+		if rob.Strategy == "" {
+			rob.Strategy = strategy.Default
+		}
+
+		if rob.Quantity == 0 {
+			rob.Quantity = rb.Quantity
+		}
+
+		if rob.OnPlanYield == "" {
+			rob.OnPlanYield = robots.PlanNotify
+		}
+
 		err = robots.ChackRobotForUpdate(rob)
+		if err == nil && robots.DirectionChanged(*rb, rob) {
+			err = robots.CheckDirection(rob)
+		}
+
+		if err == nil {
+			err = robots.CheckSchedule(&rob, h.userLocation(id))
+		}
+
 		if err != nil {
 			http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+			return
+		}
+
+		// запущенный робот обновляется на ходу, воркер сохраняет позицию,
+		// но открытая позиция остается на старом тикере, поэтому тикер меняется только без нее
+		if rob.Ticker != rb.Ticker && h.holdsPosition(rb) {
+			http.Error(w, "close the position before changing ticker", http.StatusConflict)
+
+			return
 		}
 
 		rob.RobotID = robotID
 
 		err = h.repoRobot.Update(&rob)
+		if errors.Cause(err) == sql.ErrNoRows {
+			http.Error(w, "robot was deleted", http.StatusConflict)
+
+			return
+		}
+
 		if err != nil {
-			h.logger.Debugf("robot was not found %s", err)
-			http.Error(w, "robot was not found", http.StatusNotFound)
+			h.logger.Errorf("failed to update robot %s", err)
+			http.Error(w, "failed to update robot", http.StatusInternalServerError)
 
 			return
 		}
+
+		h.warnCalendar(w, &rob)
+		h.engine.Reload(robotID)
 	} else {
 		w.WriteHeader(http.StatusForbidden)
 	}
 }
 
+// holdsPosition есть ли у запущенного робота открытая позиция, при ошибке чтения считаем, что есть
+func (h *Handler) holdsPosition(rob *robots.Robot) bool {
+	if !rob.Status.IsLive() {
+		return false
+	}
+
+	st, err := h.repoState.GetState(rob.RobotID)
+	if err != nil {
+		h.logger.Errorf("failed to get state of robot %s", err)
+
+		return true
+	}
+
+	return st != nil && strategy.ParseSide(st.Side) != strategy.Flat
+}
+
 // WSRobotsUpdate ...
 func (h *Handler) WSRobotsUpdate(w http.ResponseWriter, r *http.Request) {
 	var upgrader = websocket.Upgrader{
@@ -824,6 +1115,8 @@ func (h *Handler) WSRobotsUpdate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		h.markHalted(robots...)
+
 		res, err := json.Marshal(robots)
 		if err != nil {
 			h.logger.Errorf("can't marshal message: %+s", err)
@@ -864,6 +1157,8 @@ func (h *Handler) WSUserRobotsUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc := h.userLocation(userID)
+
 	for {
 		time.Sleep(sec * time.Second)
 
@@ -875,6 +1170,9 @@ func (h *Handler) WSUserRobotsUpdate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		h.markHalted(robots...)
+		localize(loc, robots...)
+
 		res, err := json.Marshal(robots)
 		if err != nil {
 			h.logger.Errorf("can't marshal message: %+s", err)
@@ -918,8 +1216,10 @@ func (h *Handler) WSSingleRobotUpdate(w http.ResponseWriter, r *http.Request) {
 
 	h.wsClients.AddConn(conn, robotID)
 
+	robotChan := h.wsClients.robotChan(robotID)
+
 	for {
-		robotData := <-h.wsClients.wsRobot[robotID]
+		robotData := <-robotChan
 
 		res, err := json.Marshal(robotData)
 		if err != nil {
@@ -942,6 +1242,78 @@ func (h *Handler) WSSingleRobotUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// WSPrices r.HandleFunc("/tickers/{ticker}/wsprices", h.WSPrices)
+func (h *Handler) WSPrices(w http.ResponseWriter, r *http.Request) {
+	var upgrader = websocket.Upgrader{
+		ReadBufferSize:  bufferSize,
+		WriteBufferSize: bufferSize,
+	}
+
+	ticker := chi.URLParam(r, "ticker")
+
+	upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Errorf("can't upgrade connection: %s", err)
+		http.Error(w, "can't upgrade connection", http.StatusInternalServerError)
+
+		return
+	}
+
+	sub := h.hub.Subscribe(ticker)
+	defer sub.Close()
+
+	for data := range sub.C {
+		res, err := json.Marshal(data)
+		if err != nil {
+			h.logger.Errorf("can't marshal message: %+s", err)
+			continue
+		}
+
+		err = conn.WriteMessage(websocket.TextMessage, res)
+		if err != nil {
+			h.logger.Debugf("can't broadcast message: %+s", err)
+			break
+		}
+	}
+
+	conn.Close()
+}
+
+// MarketSubscribers r.Get("/market/subscribers", h.MarketSubscribers)
+func (h *Handler) MarketSubscribers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-type", jsonType)
+
+	err := JSONwriter(w, h.hub.Subscribers())
+	if err != nil {
+		h.logger.Errorf("failed to write subscribers %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// MarketFeeds r.Get("/market/feeds", h.MarketFeeds)
+func (h *Handler) MarketFeeds(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-type", jsonType)
+
+	err := JSONwriter(w, h.hub.Stats())
+	if err != nil {
+		h.logger.Errorf("failed to write feeds %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// EngineWorkers r.Get("/engine/workers", h.EngineWorkers)
+func (h *Handler) EngineWorkers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-type", jsonType)
+
+	err := JSONwriter(w, h.engine.Statuses())
+	if err != nil {
+		h.logger.Errorf("failed to write workers %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
 // GetRobot r.Get("robot/{ID}", h.GetRobot)
 func (h *Handler) GetRobot(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("Authorization")
@@ -971,6 +1343,16 @@ func (h *Handler) GetRobot(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if !robot.DeletedAt.Valid {
+			st, err := h.repoState.GetState(robotID)
+			if err != nil {
+				h.logger.Errorf("failed to get state of robot %s", err)
+			}
+
+			robot.Trail = robots.TrailFor(robot, st)
+			robot.NextSession = robot.NextSessionAfter(clock.Now())
+			h.markHalted(robot)
+			localize(h.tokenLocation(token), robot)
+
 			if content == jsonType {
 				err = JSONwriter(w, robot)
 				if err != nil {
@@ -989,155 +1371,732 @@ func (h *Handler) GetRobot(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// WSClients ...
-type wsClients struct {
-	wsConn  map[int][]*websocket.Conn
-	wsRobot map[int]chan *robots.Robot
-	Robots  map[int]*Custom
-	sync.Mutex
-}
-
-// Custom ...
-type Custom struct {
-	Robot     *robots.Robot
-	Activated bool
-}
-
-// AddConn ...
-func (ws *wsClients) AddConn(conn *websocket.Conn, robotID int) {
-	ws.Mutex.Lock()
-	ws.wsConn[robotID] = append(ws.wsConn[robotID], conn)
-	ws.Mutex.Unlock()
-}
-
-// RemoveIndex сли коннект отвалился
-func RemoveIndex(s []*websocket.Conn, index int) []*websocket.Conn {
-	return append(s[:index], s[index+1:]...)
-}
+// GetRobotDeals r.Get("robot/{ID}/deals", h.GetRobotDeals)
+func (h *Handler) GetRobotDeals(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	content := r.Header.Get("Content-type")
 
-// JSONwriter ...
-func JSONwriter(w io.Writer, data interface{}) error {
-	jsonData, err := json.MarshalIndent(data, "  ", "    ")
+	robotID, err := strconv.Atoi(chi.URLParam(r, "ID"))
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal robots %s")
+		http.Error(w, "bad id param", http.StatusBadRequest)
+		return
 	}
 
-	_, err = w.Write(jsonData)
+	ses, err := h.repoSession.FindByToken(token)
 	if err != nil {
-		return errors.Wrap(err, "failed to write data %s")
+		h.logger.Debugf("session was not found %s", err)
+		http.Error(w, "session was not found", http.StatusNotFound)
+
+		return
 	}
 
-	return nil
-}
+	if sessions.CheckValidSes(token, ses) {
+		_, err := h.repoRobot.GetRobot(robotID)
+		if err != nil {
+			h.logger.Debugf("robot was not found %s", err)
+			http.Error(w, "robot was not found", http.StatusNotFound)
 
-// New ...
-func (h *Handler) New(robotData *robots.Robot, repoRobot *postgres.RobotStorage) { //nolint
-	go func() {
-		isBuying := true
-		isSelling := false
+			return
+		}
 
-		var bouht, sold float64
+		type dls struct {
+			RobotID int
+			Deals   []*deals.Deal
+		}
 
-		res, err := h.streamer.Price(context.Background(), &fintech.PriceRequest{Ticker: robotData.Ticker})
+		robotDeals, err := h.repoDeal.GetRobotDeals(robotID)
 		if err != nil {
-			h.logger.Fatalf("stream failed %s", err)
-		}
+			h.logger.Errorf("failed to get deals %s", err)
+			http.Error(w, "failed to get deals", http.StatusInternalServerError)
 
-		h.logger.Debugf("stream is starting with ticker:%s and robotID:%v", robotData.Ticker, robotData.RobotID)
+			return
+		}
 
-		for {
-			if !(time.Now().Add(hour*time.Hour).Before(robotData.PlanEnd.Time) && time.Now().Add(hour*time.Hour).After(robotData.PlanStart.Time)) {
-				h.wsClients.Robots[robotData.RobotID].Activated = false
-				h.logger.Debugf("stream has ended with ticker:%s and robotID:%v", robotData.Ticker, robotData.RobotID)
+		if content == jsonType {
+			err = JSONwriter(w, robotDeals)
+			if err != nil {
+				http.Error(w, "failed to get deals", http.StatusInternalServerError)
 
 				return
 			}
+		} else {
+			h.renderTemplate(w, "robot_deals", dls{RobotID: robotID, Deals: robotDeals})
+		}
+	} else {
+		w.WriteHeader(http.StatusForbidden)
+	}
+}
 
-			data, err := res.Recv()
-			if err != nil {
-				if err == io.EOF {
-					break
-				}
+// BacktestRobot r.Post("robot/{ID}/backtest", h.BacktestRobot)
+// котировки берутся из записанной истории ?from=&to=, загруженного файла "file", тела text/csv или файла ?file= из ./data,
+// ?buy_price= и ?sell_price= подменяют пороги робота только на время прогона
+func (h *Handler) BacktestRobot(w http.ResponseWriter, r *http.Request) { //nolint
+	token := r.Header.Get("Authorization")
 
-				h.logger.Fatalf("can't receive from server: %v", err)
-			}
+	robotID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+		return
+	}
 
-			if isBuying {
-				if robotData.BuyPrice >= data.BuyPrice {
-					isBuying = false
-					isSelling = true
-					bouht = data.BuyPrice
-				} else {
-					continue
-				}
-			}
+	ses, err := h.repoSession.FindByToken(token)
+	if err != nil {
+		h.logger.Debugf("session was not found %s", err)
+		http.Error(w, "session was not found", http.StatusNotFound)
+
+		return
+	}
 
-			if isSelling {
-				if robotData.SellPrice <= data.SellPrice {
-					isBuying = true
-					isSelling = false
-					sold = data.SellPrice
+	if !sessions.CheckValidSes(token, ses) {
+		w.WriteHeader(http.StatusForbidden)
 
-					robotData.DealsCount++
-					robotData.FactYield += sold - bouht
+		return
+	}
+
+	robot, err := h.repoRobot.GetRobot(robotID)
+	if err != nil {
+		h.logger.Debugf("robot was not found %s", err)
+		http.Error(w, "robot was not found", http.StatusNotFound)
 
-					if h.wsClients.wsConn[robotData.RobotID] == nil {
-						err = repoRobot.UpdateActual(robotData)
-						if err != nil {
-							h.logger.Fatalf("failde to update data in stream %s", err)
-						}
+		return
+	}
 
-						continue
-					}
+	values := r.URL.Query()
 
-					h.wsClients.wsRobot[robotData.RobotID] <- robotData
+	if v := values.Get("buy_price"); v != "" {
+		robot.BuyPrice, err = decimal.NewFromString(v)
+		if err != nil {
+			http.Error(w, "bad buy price", http.StatusBadRequest)
 
-					err = repoRobot.UpdateActual(robotData)
-					if err != nil {
-						h.logger.Fatalf("failed to update robots by stream %s", err)
-					}
-				} else {
-					continue
-				}
-			}
+			return
+		}
+	}
+
+	if v := values.Get("sell_price"); v != "" {
+		robot.SellPrice, err = decimal.NewFromString(v)
+		if err != nil {
+			http.Error(w, "bad sell price", http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	ticks, err := h.backtestTicks(r, robot.Ticker)
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	result, err := backtest.Run(*robot, ticks, h.calendar)
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	err = JSONwriter(w, result)
+	if err != nil {
+		h.logger.Errorf("failed to write backtest %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// backtestTicks берет котировки из записанной истории или из csv
+func (h *Handler) backtestTicks(r *http.Request, ticker string) ([]*fintech.PriceResponse, error) {
+	values := r.URL.Query()
+
+	if values.Get("from") != "" || values.Get("to") != "" {
+		from, to, err := parsePeriod(values.Get("from"), values.Get("to"))
+		if err != nil {
+			return nil, err
+		}
+
+		recorded, err := h.repoQuote.Find(ticker, from, to)
+		if err != nil {
+			h.logger.Errorf("failed to get quotes %s", err)
+
+			return nil, errors.New("failed to get quotes")
+		}
+
+		ticks := make([]*fintech.PriceResponse, 0, len(recorded))
+		for _, q := range recorded {
+			ticks = append(ticks, q.ToPrice())
 		}
-	}()
+
+		return ticks, nil
+	}
+
+	src, err := backtestSource(r)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	return backtest.ReadCSV(src)
 }
 
-// Robot ...
-func (h *Handler) Robot(repoRobot *postgres.RobotStorage) {
-	for {
-		actualInfoRobots, err := repoRobot.GetAllNonDeletedRobots()
+// backtestSource выбирает, откуда читать котировки для бэктеста
+func backtestSource(r *http.Request) (io.ReadCloser, error) {
+	if name := r.URL.Query().Get("file"); name != "" {
+		f, err := os.Open(filepath.Join(backtestDir, filepath.Base(name)))
 		if err != nil {
-			h.logger.Fatalf("failed to get robots on stream %s", err)
+			return nil, errors.New("can't open file " + name)
 		}
 
-		for _, v := range actualInfoRobots {
-			if _, ok := h.wsClients.Robots[v.RobotID]; ok {
-				h.wsClients.Robots[v.RobotID].Robot.ActivatedAt = v.ActivatedAt
-				h.wsClients.Robots[v.RobotID].Robot.DeactivatedAt = v.DeactivatedAt
-				h.wsClients.Robots[v.RobotID].Robot.PlanStart = v.PlanStart
-				h.wsClients.Robots[v.RobotID].Robot.PlanEnd = v.PlanEnd
-				h.wsClients.Robots[v.RobotID].Robot.BuyPrice = v.BuyPrice
-				h.wsClients.Robots[v.RobotID].Robot.SellPrice = v.SellPrice
-				h.wsClients.Robots[v.RobotID].Robot.Ticker = v.Ticker
-				h.wsClients.Robots[v.RobotID].Robot.PlanYield = v.PlanYield
+		return f, nil
+	}
 
-				if v.IsActive && time.Now().Add(hour*time.Hour).Before(v.PlanEnd.Time) && time.Now().Add(hour*time.Hour).After(v.PlanStart.Time) && !h.wsClients.Robots[v.RobotID].Activated {
-					h.wsClients.Robots[v.RobotID].Activated = true
+	if strings.HasPrefix(r.Header.Get("Content-type"), csvType) {
+		return r.Body, nil
+	}
 
-					h.New(v, repoRobot)
-				}
-			} else {
-				h.wsClients.Robots[v.RobotID] = &Custom{
-					Robot:     v,
-					Activated: false,
-				}
+	if err := r.ParseMultipartForm(maxUpload); err != nil {
+		return nil, errors.New("send csv as file, text/csv body or ?file=")
+	}
 
-				h.wsClients.wsRobot[v.RobotID] = make(chan *robots.Robot)
-			}
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, errors.New("send csv as file, text/csv body or ?file=")
+	}
+
+	return f, nil
+}
+
+// GetTickerQuotes r.Get("/tickers/{ticker}/quotes", h.GetTickerQuotes) ?from=&to= в RFC3339
+func (h *Handler) GetTickerQuotes(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	ticker := chi.URLParam(r, "ticker")
+
+	ses, err := h.repoSession.FindByToken(token)
+	if err != nil {
+		h.logger.Debugf("session was not found %s", err)
+		http.Error(w, "session was not found", http.StatusNotFound)
+
+		return
+	}
+
+	if !sessions.CheckValidSes(token, ses) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	from, to, err := parsePeriod(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	recorded, err := h.repoQuote.Find(ticker, from, to)
+	if err != nil {
+		h.logger.Errorf("failed to get quotes %s", err)
+		http.Error(w, "failed to get quotes", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	err = JSONwriter(w, recorded)
+	if err != nil {
+		h.logger.Errorf("failed to write quotes %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// parsePeriod разбирает границы периода в RFC3339, по умолчанию последние сутки
+func parsePeriod(fromStr, toStr string) (time.Time, time.Time, error) {
+	var err error
+
+	to := clock.Now()
+	if toStr != "" {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("bad to param")
+		}
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if fromStr != "" {
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("bad from param")
+		}
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from shoud be erlier than to")
+	}
+
+	return from, to, nil
+}
+
+// WSClients ...
+type wsClients struct {
+	wsConn  map[int][]*websocket.Conn
+	wsRobot map[int]chan *robots.Robot
+	sync.Mutex
+}
+
+var _ engine.Notifier = &wsClients{}
+
+func (ws *wsClients) robotChan(robotID int) chan *robots.Robot {
+	ws.Mutex.Lock()
+	defer ws.Mutex.Unlock()
+
+	ch, ok := ws.wsRobot[robotID]
+	if !ok {
+		ch = make(chan *robots.Robot)
+		ws.wsRobot[robotID] = ch
+	}
+
+	return ch
+}
+
+// Notify отдает состояние робота его ws клиентам, если они есть
+func (ws *wsClients) Notify(rob *robots.Robot) {
+	ws.Mutex.Lock()
+	conns := len(ws.wsConn[rob.RobotID])
+	ws.Mutex.Unlock()
+
+	if conns == 0 {
+		return
+	}
+
+	select {
+	case ws.robotChan(rob.RobotID) <- rob:
+	default:
+	}
+}
+
+// AddConn ...
+func (ws *wsClients) AddConn(conn *websocket.Conn, robotID int) {
+	ws.Mutex.Lock()
+	ws.wsConn[robotID] = append(ws.wsConn[robotID], conn)
+	ws.Mutex.Unlock()
+}
+
+// RemoveIndex сли коннект отвалился
+func RemoveIndex(s []*websocket.Conn, index int) []*websocket.Conn {
+	return append(s[:index], s[index+1:]...)
+}
+
+// userLocation зона пользователя, UTC если пользователя не найти
+func (h *Handler) userLocation(userID int) *time.Location {
+	u, err := h.repoUser.Find(userID)
+	if err != nil {
+		return time.UTC
+	}
+
+	return u.Location()
+}
+
+// tokenLocation зона пользователя, сделавшего запрос
+func (h *Handler) tokenLocation(token string) *time.Location {
+	id, err := sessions.DecodeToken(token)
+	if err != nil {
+		return time.UTC
+	}
+
+	return h.userLocation(id)
+}
+
+// localize переводит времена роботов в зону пользователя
+func localize(loc *time.Location, rbts ...*robots.Robot) {
+	for _, rob := range rbts {
+		rob.In(loc)
+	}
+}
+
+// markHalted отмечает в роботах глобальную остановку торговли
+func (h *Handler) markHalted(rbts ...*robots.Robot) {
+	halted := h.engine.Halted().Halted
+
+	for _, rob := range rbts {
+		rob.TradingHalted = halted
+	}
+}
+
+// Halt r.Post("/api/v1/admin/halt", h.Halt)
+func (h *Handler) Halt(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r.Header.Get("Authorization")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	flatten, err := strconv.ParseBool(r.FormValue("flatten"))
+	if err != nil && r.FormValue("flatten") != "" {
+		http.Error(w, "bad flatten param", http.StatusBadRequest)
+
+		return
+	}
+
+	st, err := h.engine.Halt(r.FormValue("reason"), flatten)
+	if err != nil {
+		h.logger.Errorf("failed to halt trading %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, st); err != nil {
+		h.logger.Errorf("failed to write halt %s", err)
+	}
+}
+
+// ResumeTrading r.Post("/api/v1/admin/resume", h.ResumeTrading)
+func (h *Handler) ResumeTrading(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r.Header.Get("Authorization")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	st, err := h.engine.Resume()
+	if err != nil {
+		h.logger.Errorf("failed to resume trading %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, st); err != nil {
+		h.logger.Errorf("failed to write halt %s", err)
+	}
+}
+
+// checkActivateLimits проверяет лимит активных роботов пользователя
+func (h *Handler) checkActivateLimits(userID int) error {
+	l, err := h.repoLimits.GetLimits(userID)
+	if err != nil {
+		return err
+	}
+
+	u, err := h.repoLimits.GetUsage(userID)
+	if err != nil {
+		return err
+	}
+
+	return l.CheckActivate(u)
+}
+
+// isAdmin проверяет, что запрос пришел от администратора с живой сессией
+func (h *Handler) isAdmin(token string) bool {
+	ses, err := h.repoSession.FindByToken(token)
+	if err != nil || !sessions.CheckValidSes(token, ses) {
+		return false
+	}
+
+	id, err := sessions.DecodeToken(token)
+	if err != nil {
+		return false
+	}
+
+	u, err := h.repoUser.Find(id)
+	if err != nil {
+		return false
+	}
+
+	return u.IsAdmin
+}
+
+// GetUserLimits r.Get("/api/v1/admin/users/{ID}/limits", h.GetUserLimits)
+func (h *Handler) GetUserLimits(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+
+		return
+	}
+
+	if !h.isAdmin(r.Header.Get("Authorization")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	l, err := h.repoLimits.GetLimits(userID)
+	if err != nil {
+		h.logger.Errorf("failed to get limits %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, l); err != nil {
+		h.logger.Errorf("failed to write limits %s", err)
+	}
+}
+
+// SetUserLimits r.Put("/api/v1/admin/users/{ID}/limits", h.SetUserLimits)
+func (h *Handler) SetUserLimits(w http.ResponseWriter, r *http.Request) {
+	var l limits.Limits
+
+	userID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+
+		return
+	}
+
+	if !h.isAdmin(r.Header.Get("Authorization")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	if err = json.Unmarshal(body, &l); err != nil {
+		http.Error(w, "bad limits", http.StatusBadRequest)
+
+		return
+	}
+
+	l.UserID = userID
+
+	if err = l.Validate(); err != nil {
+		http.Error(w, fmt.Sprintln(err), http.StatusBadRequest)
+
+		return
+	}
+
+	if _, err = h.repoUser.Find(userID); err != nil {
+		http.Error(w, "user not found", http.StatusNotFound)
+
+		return
+	}
+
+	if err = h.repoLimits.SetLimits(&l); err != nil {
+		h.logger.Errorf("failed to set limits %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, l); err != nil {
+		h.logger.Errorf("failed to write limits %s", err)
+	}
+}
+
+// checkFunds хватит ли денег на счете пользователя на вход робота в позицию
+func (h *Handler) checkFunds(rob *robots.Robot) error {
+	a, err := h.repoAccount.GetAccount(rob.OwnerUserID)
+	if err != nil {
+		return err
+	}
+
+	return a.CheckFunds(rob.EntryAmount())
+}
+
+// ownerOrAdmin запрос пришел от самого пользователя userID с живой сессией или от администратора
+func (h *Handler) ownerOrAdmin(token string, userID int) bool {
+	ses, err := h.repoSession.FindByToken(token)
+	if err != nil || !sessions.CheckValidSes(token, ses) {
+		return false
+	}
+
+	id, err := sessions.DecodeToken(token)
+	if err != nil {
+		return false
+	}
+
+	return id == userID || h.isAdmin(token)
+}
+
+// GetPortfolio r.Get("/api/v1/users/{ID}/portfolio", h.GetPortfolio)
+// позиции оцениваются по последней записанной котировке: long по цене продажи, short по цене покупки
+func (h *Handler) GetPortfolio(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	content := r.Header.Get("Content-type")
+
+	userID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+
+		return
+	}
+
+	if !h.ownerOrAdmin(token, userID) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	a, err := h.repoAccount.GetAccount(userID)
+	if err != nil {
+		h.logger.Errorf("failed to get account %s", err)
+		http.Error(w, "failed to get account", http.StatusInternalServerError)
+
+		return
+	}
+
+	hs, err := h.repoAccount.GetHoldings(userID)
+	if err != nil {
+		h.logger.Errorf("failed to get holdings %s", err)
+		http.Error(w, "failed to get holdings", http.StatusInternalServerError)
+
+		return
+	}
+
+	marks := make(map[string]decimal.Decimal)
+
+	for _, hold := range hs {
+		if hold.Quantity == 0 {
+			continue
+		}
+
+		q, err := h.repoQuote.Latest(hold.Ticker)
+		if err != nil {
+			h.logger.Errorf("failed to get quote %s", err)
+		}
+
+		if q == nil {
+			continue
 		}
 
-		time.Sleep(hour * time.Second)
+		if hold.Quantity > 0 {
+			marks[hold.Ticker] = q.SellPrice
+		} else {
+			marks[hold.Ticker] = q.BuyPrice
+		}
+	}
+
+	p := accounts.NewPortfolio(a, hs, marks)
+
+	if content == jsonType {
+		if err = JSONwriter(w, p); err != nil {
+			http.Error(w, "failed to get portfolio", http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	h.renderTemplate(w, "portfolio", p)
+}
+
+// GetTransactions r.Get("/api/v1/users/{ID}/transactions", h.GetTransactions)
+func (h *Handler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+
+		return
+	}
+
+	if !h.ownerOrAdmin(r.Header.Get("Authorization"), userID) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	ts, err := h.repoAccount.GetTransactions(userID)
+	if err != nil {
+		h.logger.Errorf("failed to get transactions %s", err)
+		http.Error(w, "failed to get transactions", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, ts); err != nil {
+		h.logger.Errorf("failed to write transactions %s", err)
+	}
+}
+
+// TransferCash r.Post("/api/v1/admin/users/{ID}/cash", h.TransferCash)
+// положительный amount пополняет счет, отрицательный выводит деньги
+func (h *Handler) TransferCash(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+	if err != nil {
+		http.Error(w, "bad id param", http.StatusBadRequest)
+
+		return
+	}
+
+	if !h.isAdmin(r.Header.Get("Authorization")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(r.FormValue("amount"))
+	if err != nil || amount.IsZero() {
+		http.Error(w, "bad amount", http.StatusBadRequest)
+
+		return
+	}
+
+	if _, err = h.repoUser.Find(userID); err != nil {
+		http.Error(w, "user not found", http.StatusNotFound)
+
+		return
+	}
+
+	t, err := h.repoAccount.Transfer(userID, amount, r.FormValue("note"))
+	if errors.Cause(err) == accounts.ErrInsufficientFunds {
+		http.Error(w, fmt.Sprint(err), http.StatusUnprocessableEntity)
+
+		return
+	}
+
+	if err != nil {
+		h.logger.Errorf("failed to transfer cash %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Add("Content-type", jsonType)
+
+	if err = JSONwriter(w, t); err != nil {
+		h.logger.Errorf("failed to write transaction %s", err)
+	}
+}
+
+// robotErrStatus запрещенный переход стадии робота отдает как 409, превышение лимитов и нехватку денег как 422,
+// остальное как 404
+func robotErrStatus(err error) int {
+	switch errors.Cause(err) {
+	case robots.ErrBadTransition:
+		return http.StatusConflict
+	case limits.ErrExceeded, accounts.ErrInsufficientFunds:
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusNotFound
+}
+
+// JSONwriter ...
+func JSONwriter(w io.Writer, data interface{}) error {
+	jsonData, err := json.MarshalIndent(data, "  ", "    ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal robots %s")
 	}
+
+	_, err = w.Write(jsonData)
+	if err != nil {
+		return errors.Wrap(err, "failed to write data %s")
+	}
+
+	return nil
 }
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"authDB/internal/broker"
+	"authDB/internal/calendar"
+	"authDB/internal/engine"
 	"authDB/internal/fintech"
+	"authDB/internal/market"
 	"authDB/internal/postgres"
+	"authDB/internal/quotes"
 	"authDB/internal/robots"
 	"authDB/pkg/logger"
 	"context"
@@ -15,12 +20,26 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 )
 
 const (
 	port  = ":8080"
 	delay = 5
+
+	minBackoff    = 1 * time.Second
+	maxBackoff    = 30 * time.Second
+	degradedAfter = 1 * time.Minute
+	syncInterval  = 3 * time.Second
+	orderTimeout  = 10 * time.Second
+
+	recordQuotes    = true
+	quotesBatch     = 500
+	quotesFlush     = 1 * time.Second
+	quotesRetention = 30 * 24 * time.Hour
+
+	calendarFile = "./data/calendar.json"
 )
 
 func main() { // nolint
@@ -48,6 +67,43 @@ func main() { // nolint
 		newLogger.Fatalf("failed to create session storage %+s", err)
 	}
 
+	repoDeal, err := postgres.NewDealStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create deal storage %+s", err)
+	}
+
+	repoState, err := postgres.NewStateStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create state storage %+s", err)
+	}
+
+	repoQuote, err := postgres.NewQuoteStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create quote storage %+s", err)
+	}
+
+	repoLimits, err := postgres.NewLimitStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create limit storage %+s", err)
+	}
+
+	repoHalt, err := postgres.NewHaltStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create halt storage %+s", err)
+	}
+
+	repoAccounts, err := postgres.NewAccountStorage(db)
+	if err != nil {
+		newLogger.Fatalf("failed to create account storage %+s", err)
+	}
+
+	tradingCalendar, err := calendar.Load(calendarFile)
+	if os.IsNotExist(errors.Cause(err)) {
+		newLogger.Warnf("no trading calendar %s, robots trade at any time", calendarFile)
+	} else if err != nil {
+		newLogger.Fatalf("failed to load trading calendar %+s", err)
+	}
+
 	conn, err := grpc.Dial("localhost:5000", grpc.WithInsecure())
 	if err != nil {
 		newLogger.Fatalf("can not connect to server: %+s", err)
@@ -58,13 +114,16 @@ func main() { // nolint
 	var wsClients = &wsClients{
 		make(map[int][]*websocket.Conn),
 		make(map[int]chan *robots.Robot),
-		make(map[int]*Custom),
 		sync.Mutex{},
 	}
 
 	templates := ParseTemplates()
 	StreamClient := fintech.NewTradingServiceClient(conn)
-	handler := newHandler(newLogger, repoUser, repoSession, repoRobot, StreamClient, templates, wsClients)
+	hub := market.NewHub(StreamClient, newLogger, market.Config{MinBackoff: minBackoff, MaxBackoff: maxBackoff})
+	supervisor := engine.NewSupervisor(newLogger, repoRobot, repoDeal, repoState, repoLimits, repoHalt, repoAccounts, hub,
+		broker.NewGRPC(StreamClient, orderTimeout), wsClients,
+		engine.Config{DegradedAfter: degradedAfter, SyncInterval: syncInterval, Calendar: tradingCalendar})
+	handler := newHandler(newLogger, repoUser, repoSession, repoRobot, repoDeal, repoState, repoLimits, repoAccounts, repoQuote, hub, supervisor, tradingCalendar, templates, wsClients)
 
 	r := chi.NewRouter()
 
@@ -86,7 +145,15 @@ func main() { // nolint
 		cancel()
 	}()
 
-	go handler.Robot(repoRobot)
+	if recordQuotes {
+		recorder := quotes.NewRecorder(newLogger, repoQuote,
+			quotes.Config{BatchSize: quotesBatch, FlushInterval: quotesFlush, Retention: quotesRetention})
+		hub.SetTap(recorder)
+
+		go recorder.Run(ctx)
+	}
+
+	go supervisor.Run(ctx)
 
 	go func() {
 		err = srv.ListenAndServe()
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"authDB/internal/pricefeed"
+	"authDB/pkg/logger"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"google.golang.org/grpc"
+)
+
+func main() {
+	configPath := flag.String("config", "", "json config with tickers, see cmd/price-streamer/config.json")
+	flag.Parse()
+
+	newLogger, err := logger.NewLogger()
+	if err != nil {
+		log.Fatalf("Could not instantiate log %+s", err)
+	}
+
+	cfg := pricefeed.DefaultConfig()
+
+	if *configPath != "" {
+		cfg, err = pricefeed.LoadConfig(*configPath)
+		if err != nil {
+			newLogger.Fatalf("failed to load config %+s", err)
+		}
+	}
+
+	lis, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		newLogger.Fatalf("failed to listen %s %+s", cfg.Addr, err)
+	}
+
+	srv := grpc.NewServer()
+	pricefeed.NewServer(cfg).Register(srv)
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+
+	go func() {
+		oscall := <-c
+		log.Printf("system call:%+v", oscall)
+		srv.GracefulStop()
+	}()
+
+	log.Printf("price streamer started on %s", cfg.Addr)
+
+	if err := srv.Serve(lis); err != nil {
+		newLogger.Fatalf("price streamer stopped %+s", err)
+	}
+
+	log.Printf("price streamer exited properly")
+}
@@ -13,7 +13,7 @@ require (
 	github.com/jackc/pgx v3.6.2+incompatible // indirect
 	github.com/lib/pq v1.5.1
 	github.com/pkg/errors v0.8.1
-	github.com/shopspring/decimal v1.2.0 // indirect
+	github.com/shopspring/decimal v1.2.0
 	gitlab.com/vadimlarionov/hello-app v1.1.1 // indirect
 	go.uber.org/zap v1.15.0
 	golang.org/x/net v0.0.0-20200506145744-7e3656a0809f // indirect
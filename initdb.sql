@@ -5,15 +5,17 @@ CREATE TABLE public.users (
     email text UNIQUE NOT NULL,
     password text NOT NULL,
     birthday text,
-    created_at timestamp NOT NULL,
-    updated_at timestamp NOT NULL
+    timezone text NOT NULL DEFAULT 'UTC',
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL,
+    is_admin boolean NOT NULL DEFAULT false
 );
 
 CREATE TABLE public.sessions (
     token text NOT NULL,
     user_id bigserial PRIMARY KEY,
-    created_at timestamp NOT NULL,
-    valid_until timestamp NOT NULL,
+    created_at timestamptz NOT NULL,
+    valid_until timestamptz NOT NULL,
     FOREIGN KEY (user_id) REFERENCES public.users(id)
 );
 
@@ -24,21 +26,136 @@ CREATE TABLE public.robots (
     is_favorite boolean NOT NULL,
     is_active boolean NOT NULL,
     ticker text NOT NULL,
-    buy_price numeric(5, 2) NOT NULL,
-    sell_price numeric(5, 2) NOT NULL,
-    plan_start timestamp NOT NULL,
-    plan_end timestamp NOT NULL,
-    plan_yield integer NOT NULL,
-    fact_yield integer,
+    buy_price numeric(18, 4) NOT NULL,
+    sell_price numeric(18, 4) NOT NULL,
+    plan_start timestamptz NOT NULL,
+    plan_end timestamptz NOT NULL,
+    plan_yield numeric(18, 4) NOT NULL,
+    fact_yield numeric(18, 4) NOT NULL DEFAULT 0,
     deals_count integer NOT NULL,
-    activated_at timestamp,
-    deactivated_at timestamp,
-    created_at timestamp NOT NULL,
-    deleted_at timestamp
+    activated_at timestamptz,
+    deactivated_at timestamptz,
+    created_at timestamptz NOT NULL,
+    deleted_at timestamptz,
+    strategy text NOT NULL DEFAULT 'threshold',
+    strategy_params jsonb NOT NULL DEFAULT '{}',
+    is_degraded boolean NOT NULL DEFAULT false,
+    status text NOT NULL DEFAULT 'draft',
+    status_reason text NOT NULL DEFAULT '',
+    status_changed_at timestamptz,
+    quantity integer NOT NULL DEFAULT 1,
+    stop_loss numeric(18, 4) NOT NULL DEFAULT 0,
+    stop_loss_percent boolean NOT NULL DEFAULT false,
+    take_profit numeric(18, 4) NOT NULL DEFAULT 0,
+    take_profit_percent boolean NOT NULL DEFAULT false,
+    trailing_stop numeric(18, 4) NOT NULL DEFAULT 0,
+    trailing_stop_percent boolean NOT NULL DEFAULT false,
+    on_plan_yield text NOT NULL DEFAULT 'notify',
+    plan_outcome text NOT NULL DEFAULT '',
+    plan_reached_at timestamptz,
+    max_loss numeric(18, 4) NOT NULL DEFAULT 0,
+    max_drawdown numeric(18, 4) NOT NULL DEFAULT 0,
+    direction text NOT NULL DEFAULT 'long',
+    schedule text NOT NULL DEFAULT '',
+    schedule_exclude text[] NOT NULL DEFAULT '{}'
 );
     -- FOREIGN KEY (owner_user_id) REFERENCES public.users(id)
     -- FOREIGN KEY (parent_robot_id) REFERENCES public.robots(id)
 
+CREATE TABLE public.deals (
+    id bigserial PRIMARY KEY,
+    robot_id integer NOT NULL,
+    side text NOT NULL,
+    price numeric(18, 4) NOT NULL,
+    quantity integer NOT NULL,
+    quote_ts timestamptz,
+    executed_at timestamptz NOT NULL,
+    order_id text NOT NULL DEFAULT '',
+    exit_reason text NOT NULL DEFAULT '',
+    position text NOT NULL DEFAULT 'long',
+    FOREIGN KEY (robot_id) REFERENCES public.robots(id)
+);
+
+CREATE INDEX deals_robot_id_idx ON public.deals (robot_id);
+
+CREATE TABLE public.robot_states (
+    robot_id integer PRIMARY KEY,
+    side text NOT NULL,
+    entry_price numeric(18, 4) NOT NULL,
+    entry_time timestamptz,
+    last_tick_ts timestamptz,
+    updated_at timestamptz NOT NULL,
+    quantity integer NOT NULL DEFAULT 0,
+    high_water numeric(18, 4) NOT NULL DEFAULT 0,
+    peak_equity numeric(18, 4) NOT NULL DEFAULT 0,
+    order_seq integer NOT NULL DEFAULT 0,
+    pending_order jsonb,
+    FOREIGN KEY (robot_id) REFERENCES public.robots(id)
+);
+
+CREATE TABLE public.quotes (
+    ticker text NOT NULL,
+    buy_price numeric(18, 4) NOT NULL,
+    sell_price numeric(18, 4) NOT NULL,
+    ts timestamptz NOT NULL
+) PARTITION BY RANGE (ts);
+
+CREATE INDEX quotes_ticker_ts_idx ON public.quotes (ticker, ts);
+
+CREATE TABLE public.user_limits (
+    user_id integer PRIMARY KEY,
+    max_active_robots integer NOT NULL DEFAULT 0,
+    max_ticker_exposure numeric(18, 4) NOT NULL DEFAULT 0,
+    max_total_exposure numeric(18, 4) NOT NULL DEFAULT 0,
+    max_deals_per_day integer NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+CREATE TABLE public.trading_halt (
+    id integer PRIMARY KEY CHECK (id = 1),
+    halted boolean NOT NULL,
+    flatten boolean NOT NULL,
+    reason text NOT NULL,
+    changed_at timestamptz NOT NULL
+);
+
+CREATE TABLE public.accounts (
+    user_id integer PRIMARY KEY,
+    cash numeric(18, 4) NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+CREATE TABLE public.holdings (
+    user_id integer NOT NULL,
+    ticker text NOT NULL,
+    quantity integer NOT NULL DEFAULT 0,
+    avg_price numeric(18, 4) NOT NULL DEFAULT 0,
+    realized_pnl numeric(18, 4) NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (user_id, ticker),
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+-- журнал только дополняется, баланс счета равен сумме amount
+CREATE TABLE public.transactions (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL,
+    kind text NOT NULL,
+    ticker text NOT NULL DEFAULT '',
+    quantity integer NOT NULL DEFAULT 0,
+    price numeric(18, 4) NOT NULL DEFAULT 0,
+    amount numeric(18, 4) NOT NULL,
+    deal_id integer NOT NULL DEFAULT 0,
+    robot_id integer NOT NULL DEFAULT 0,
+    note text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+CREATE INDEX transactions_user_id_idx ON public.transactions (user_id);
+
 
 INSERT INTO public.posts (title, description, price) VALUES ('post3', 'desc3', 110.99);
 
@@ -47,4 +164,4 @@ VALUES (1, 'ref1', 1),
        (2, 'ref2', 1),
        (3, 'ref1', 2),
        (4, 'ref1', 2),
-       (5, 'ref1', 2);
\ No newline at end of file
+       (5, 'ref1', 2);
@@ -0,0 +1,215 @@
+package accounts
+
+import (
+	"authDB/internal/deals"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInsufficientFunds на счете не хватает денег
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Виды операций журнала
+const (
+	KindDeposit  = "deposit"
+	KindWithdraw = "withdraw"
+	KindBuy      = "buy"
+	KindSell     = "sell"
+)
+
+// Account денежный счет пользователя
+type Account struct {
+	UserID    int
+	Cash      decimal.Decimal
+	UpdatedAt time.Time
+}
+
+// Holding бумаги пользователя по тикеру, отрицательное количество означает short
+type Holding struct {
+	Ticker   string
+	Quantity int
+	// AvgPrice средняя цена открытой позиции
+	AvgPrice decimal.Decimal
+	// RealizedPnL результат уже закрытых частей позиции
+	RealizedPnL decimal.Decimal
+	UpdatedAt   time.Time
+}
+
+// Transaction запись журнала движения денег, журнал только дополняется
+type Transaction struct {
+	ID       int
+	UserID   int
+	Kind     string
+	Ticker   string
+	Quantity int
+	Price    decimal.Decimal
+	// Amount изменение денег на счете: зачисление положительное, списание отрицательное
+	Amount decimal.Decimal
+	// DealID сделка робота, ноль у пополнений и выводов
+	DealID    int
+	RobotID   int
+	Note      string
+	CreatedAt time.Time
+}
+
+// Storage хранилище счетов, позиций и журнала.
+// Сделки роботов проводятся по счету вместе с сохранением сделки, см. deals.Deals
+type Storage interface {
+	// GetAccount возвращает пустой счет, если строки счета еще нет
+	GetAccount(userID int) (*Account, error)
+	GetHoldings(userID int) ([]*Holding, error)
+	GetTransactions(userID int) ([]*Transaction, error)
+	// Transfer зачисляет положительную сумму и списывает отрицательную, не уводя счет в минус
+	Transfer(userID int, amount decimal.Decimal, note string) (*Transaction, error)
+}
+
+// CheckFunds хватит ли денег на сделку стоимостью amount
+func (a *Account) CheckFunds(amount decimal.Decimal) error {
+	if a.Cash.LessThan(amount) {
+		return errors.Wrap(ErrInsufficientFunds, "need "+amount.StringFixed(2)+", cash "+a.Cash.StringFixed(2))
+	}
+
+	return nil
+}
+
+// ForDeal запись журнала по сделке: покупка списывает деньги, продажа зачисляет
+func ForDeal(userID int, ticker string, d *deals.Deal) *Transaction {
+	t := &Transaction{
+		UserID:   userID,
+		Kind:     KindBuy,
+		Ticker:   ticker,
+		Quantity: d.Quantity,
+		Price:    d.Price,
+		Amount:   d.Price.Mul(decimal.NewFromInt(int64(d.Quantity))).Neg(),
+		DealID:   d.DealID,
+		RobotID:  d.RobotID,
+	}
+
+	if d.Side == deals.Sell {
+		t.Kind = KindSell
+		t.Amount = t.Amount.Neg()
+	}
+
+	return t
+}
+
+// Opens открывает ли сделка side позицию или наращивает ее, а не закрывает
+func (h *Holding) Opens(side string) bool {
+	return h.Quantity == 0 || (h.Quantity > 0) == (side == deals.Buy)
+}
+
+// Apply меняет позицию на сделку и возвращает зафиксированный ею результат.
+// Часть сделки против позиции закрывает ее по средней цене, остаток открывает или наращивает позицию
+func (h *Holding) Apply(side string, quantity int, price decimal.Decimal) decimal.Decimal {
+	delta := quantity
+	if side == deals.Sell {
+		delta = -quantity
+	}
+
+	realized := decimal.Zero
+
+	if h.Quantity != 0 && (h.Quantity > 0) != (delta > 0) {
+		closed := abs(delta)
+		if abs(h.Quantity) < closed {
+			closed = abs(h.Quantity)
+		}
+
+		diff := price.Sub(h.AvgPrice)
+		if h.Quantity < 0 {
+			diff = diff.Neg()
+		}
+
+		realized = diff.Mul(decimal.NewFromInt(int64(closed)))
+	}
+
+	next := h.Quantity + delta
+
+	switch {
+	case next == 0:
+		h.AvgPrice = decimal.Zero
+	case h.Quantity == 0 || (h.Quantity > 0) != (next > 0):
+		h.AvgPrice = price
+	case (h.Quantity > 0) == (delta > 0):
+		cost := h.AvgPrice.Mul(decimal.NewFromInt(int64(abs(h.Quantity)))).Add(price.Mul(decimal.NewFromInt(int64(abs(delta)))))
+		h.AvgPrice = cost.Div(decimal.NewFromInt(int64(abs(next))))
+	}
+
+	h.Quantity = next
+	h.RealizedPnL = h.RealizedPnL.Add(realized)
+
+	return realized
+}
+
+// Position позиция портфеля, оцененная по последней котировке
+type Position struct {
+	Ticker   string
+	Quantity int
+	AvgPrice decimal.Decimal
+	// LastPrice цена, по которой позиция закрылась бы сейчас: продажа для long, покупка для short
+	LastPrice     decimal.Decimal
+	MarketValue   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+	RealizedPnL   decimal.Decimal
+}
+
+// Portfolio деньги и позиции пользователя
+type Portfolio struct {
+	UserID      int
+	Cash        decimal.Decimal
+	Positions   []*Position
+	MarketValue decimal.Decimal
+	// Equity деньги плюс стоимость позиций
+	Equity        decimal.Decimal
+	RealizedPnL   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+}
+
+// NewPortfolio оценивает позиции по ценам marks, позиция без цены оценивается по средней
+func NewPortfolio(a *Account, hs []*Holding, marks map[string]decimal.Decimal) *Portfolio {
+	p := &Portfolio{UserID: a.UserID, Cash: a.Cash, Positions: []*Position{}}
+
+	for _, h := range hs {
+		p.RealizedPnL = p.RealizedPnL.Add(h.RealizedPnL)
+
+		if h.Quantity == 0 {
+			continue
+		}
+
+		last, ok := marks[h.Ticker]
+		if !ok {
+			last = h.AvgPrice
+		}
+
+		qty := decimal.NewFromInt(int64(h.Quantity))
+		pos := &Position{
+			Ticker:        h.Ticker,
+			Quantity:      h.Quantity,
+			AvgPrice:      h.AvgPrice,
+			LastPrice:     last,
+			MarketValue:   last.Mul(qty),
+			UnrealizedPnL: last.Sub(h.AvgPrice).Mul(qty),
+			RealizedPnL:   h.RealizedPnL,
+		}
+
+		p.Positions = append(p.Positions, pos)
+		p.MarketValue = p.MarketValue.Add(pos.MarketValue)
+		p.UnrealizedPnL = p.UnrealizedPnL.Add(pos.UnrealizedPnL)
+	}
+
+	sort.Slice(p.Positions, func(i, j int) bool { return p.Positions[i].Ticker < p.Positions[j].Ticker })
+
+	p.Equity = p.Cash.Add(p.MarketValue)
+
+	return p
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
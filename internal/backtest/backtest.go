@@ -0,0 +1,124 @@
+package backtest
+
+import (
+	"authDB/internal/calendar"
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"authDB/internal/robots"
+	"authDB/internal/trading"
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Point точка кривой капитала
+type Point struct {
+	Ts     time.Time
+	Equity decimal.Decimal
+}
+
+// Result результат прогона робота по истории котировок
+type Result struct {
+	RobotID     int
+	Ticks       int
+	Deals       []*deals.Deal
+	DealsCount  int
+	FactYield   decimal.Decimal
+	MaxDrawdown decimal.Decimal
+	Equity      []Point
+	// RiskStopped лимит, на котором робот был бы остановлен
+	RiskStopped string `json:",omitempty"`
+	// PlanOutcome что сделал бы робот при достижении плановой доходности
+	PlanOutcome string `json:",omitempty"`
+}
+
+// Run прогоняет котировки через ту же торговую логику, что и живой воркер,
+// торговые часы берутся из cal по времени котировки. Робот передается по значению и в базе не меняется
+func Run(rob robots.Robot, ticks []*fintech.PriceResponse, cal *calendar.Calendar) (*Result, error) {
+	rob.DealsCount = 0
+	rob.FactYield = decimal.Zero
+	rob.PlanReachedAt = sql.NullTime{}
+
+	trader, err := trading.New(&rob)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		RobotID: rob.RobotID,
+		Ticks:   len(ticks),
+		Equity:  make([]Point, 0, len(ticks)),
+	}
+
+	var peak decimal.Decimal
+
+	record := func(fill *trading.Fill, ts time.Time) {
+		res.Deals = append(res.Deals, &deals.Deal{
+			RobotID:    rob.RobotID,
+			Side:       fill.Side,
+			Price:      fill.Price,
+			Quantity:   fill.Quantity,
+			QuoteTs:    sql.NullTime{Time: ts, Valid: !ts.IsZero()},
+			ExecutedAt: ts,
+			ExitReason: fill.Reason,
+			Position:   fill.Position.String(),
+		})
+	}
+
+	for i, data := range ticks {
+		ts := trading.QuoteTime(data)
+
+		// как и живой воркер, при нарушении лимита закрываем позицию и останавливаемся
+		if breach := trader.Risk(data); breach != "" {
+			if fill := trader.Close(data, deals.ExitRiskStop); fill != nil {
+				trader.Execute(fill, ts)
+				record(fill, ts)
+			}
+
+			res.RiskStopped = breach
+		} else if fill := trader.Signal(data); fill != nil {
+			// как и живой воркер, вне торговых часов биржи позиции не открываются, выходы разрешены
+			if !fill.Open || cal.Open(rob.Ticker, ts) {
+				trader.Execute(fill, ts)
+				record(fill, ts)
+			}
+		}
+
+		// действие по плановой доходности то же, что у живого воркера: close закрывает позицию,
+		// close и deactivate останавливают робота
+		stopped := false
+
+		if action, fill := trader.Plan(data); res.RiskStopped == "" && action != "" {
+			if fill != nil {
+				trader.Execute(fill, ts)
+				record(fill, ts)
+			}
+
+			trader.PlanDone()
+
+			res.PlanOutcome = action.Outcome()
+			stopped = action != robots.PlanNotify
+		}
+
+		equity := trader.FactYield.Add(trader.Unrealized(data))
+		res.Equity = append(res.Equity, Point{Ts: ts, Equity: equity})
+
+		if i == 0 || equity.GreaterThan(peak) {
+			peak = equity
+		}
+
+		if dd := peak.Sub(equity); dd.GreaterThan(res.MaxDrawdown) {
+			res.MaxDrawdown = dd
+		}
+
+		if res.RiskStopped != "" || stopped {
+			break
+		}
+	}
+
+	res.DealsCount = trader.DealsCount
+	res.FactYield = trader.FactYield
+
+	return res, nil
+}
@@ -0,0 +1,95 @@
+package backtest
+
+import (
+	"authDB/internal/calendar"
+	"authDB/internal/fintech"
+	"authDB/internal/robots"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/shopspring/decimal"
+)
+
+func quotes(at time.Time, prices ...[2]float64) []*fintech.PriceResponse {
+	ticks := make([]*fintech.PriceResponse, 0, len(prices))
+
+	for i, p := range prices {
+		ts, _ := ptypes.TimestampProto(at.Add(time.Duration(i) * time.Second)) // nolint
+		ticks = append(ticks, &fintech.PriceResponse{BuyPrice: p[0], SellPrice: p[1], Ts: ts})
+	}
+
+	return ticks
+}
+
+func TestRunFollowsPlanYieldAction(t *testing.T) {
+	// покупка по 10, продажа по 12 дает 2 при плане 1, потом цена снова у порога покупки
+	ticks := quotes(time.Now(), [2]float64{10, 9.9}, [2]float64{12.1, 12}, [2]float64{10, 9.9}, [2]float64{12.1, 12})
+
+	cases := []struct {
+		action  robots.PlanAction
+		deals   int
+		outcome string
+	}{
+		{robots.PlanNotify, 4, "notified"},
+		{robots.PlanDeactivate, 2, "deactivated"},
+		{robots.PlanClose, 2, "closed"},
+	}
+
+	for _, c := range cases {
+		rob := robots.Robot{
+			BuyPrice:    decimal.NewFromInt(10),
+			SellPrice:   decimal.NewFromInt(12),
+			PlanYield:   decimal.NewFromInt(1),
+			OnPlanYield: c.action,
+			Direction:   robots.DirectionLong,
+		}
+
+		res, err := Run(rob, ticks, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Deals) != c.deals || res.PlanOutcome != c.outcome {
+			t.Errorf("%s: %d deals and outcome %q, want %d and %q", c.action, len(res.Deals), res.PlanOutcome, c.deals, c.outcome)
+		}
+	}
+}
+
+func TestRunSkipsEntriesOutsideSessions(t *testing.T) {
+	cal, err := calendar.Load("../../data/calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rob := robots.Robot{
+		Ticker:    "SBER",
+		BuyPrice:  decimal.NewFromInt(10),
+		SellPrice: decimal.NewFromInt(12),
+		Direction: robots.DirectionLong,
+	}
+
+	// суббота, биржа закрыта
+	saturday := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	res, err := Run(rob, quotes(saturday, [2]float64{10, 9.9}, [2]float64{12.1, 12}), cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Deals) != 0 {
+		t.Fatalf("%d deals on a weekend, want none", len(res.Deals))
+	}
+
+	// понедельник в 12:00 по Москве, торги идут
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	res, err = Run(rob, quotes(monday, [2]float64{10, 9.9}, [2]float64{12.1, 12}), cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Deals) != 2 {
+		t.Fatalf("%d deals in a session, want 2", len(res.Deals))
+	}
+}
@@ -0,0 +1,70 @@
+package backtest
+
+import (
+	"authDB/internal/fintech"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+// ReadCSV читает котировки в формате ts,buy_price,sell_price, где ts в RFC3339.
+// Строка заголовка необязательна
+func ReadCSV(r io.Reader) ([]*fintech.PriceResponse, error) {
+	var ticks []*fintech.PriceResponse
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "bad csv")
+		}
+
+		if line == 1 && rec[0] == "ts" {
+			continue
+		}
+
+		tick, err := parseRecord(rec)
+		if err != nil {
+			return nil, errors.WithMessage(err, "bad csv line "+strconv.Itoa(line))
+		}
+
+		ticks = append(ticks, tick)
+	}
+
+	return ticks, nil
+}
+
+func parseRecord(rec []string) (*fintech.PriceResponse, error) {
+	t, err := time.Parse(time.RFC3339, rec[0])
+	if err != nil {
+		return nil, errors.New("bad ts")
+	}
+
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil, errors.New("bad ts")
+	}
+
+	buy, err := strconv.ParseFloat(rec[1], 64)
+	if err != nil {
+		return nil, errors.New("bad buy price")
+	}
+
+	sell, err := strconv.ParseFloat(rec[2], 64)
+	if err != nil {
+		return nil, errors.New("bad sell price")
+	}
+
+	return &fintech.PriceResponse{BuyPrice: buy, SellPrice: sell, Ts: ts}, nil
+}
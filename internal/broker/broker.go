@@ -0,0 +1,133 @@
+package broker
+
+import (
+	"authDB/internal/clock"
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"context"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNotFilled заявка снята или отклонена биржей и сделки не было
+var ErrNotFilled = errors.New("order was not filled")
+
+// Order заявка робота
+type Order struct {
+	// ClientOrderID постоянный номер заявки: повтор с тем же номером биржа не исполняет второй раз,
+	// а возвращает первую заявку
+	ClientOrderID string
+	Ticker        string
+	Side          string
+	Quantity      int64
+	LimitPrice    decimal.Decimal
+}
+
+// Execution подтвержденное биржей исполнение заявки
+type Execution struct {
+	OrderID  string
+	Quantity int64
+	Price    decimal.Decimal
+	Ts       time.Time
+}
+
+// Broker выставляет заявки и дожидается их исполнения
+type Broker interface {
+	Execute(ctx context.Context, o *Order) (*Execution, error)
+}
+
+var _ Broker = &GRPC{}
+
+// GRPC брокер поверх TradingService
+type GRPC struct {
+	client  fintech.TradingServiceClient
+	timeout time.Duration
+}
+
+// NewGRPC timeout ограничивает ожидание исполнения, по нему заявка снимается
+func NewGRPC(client fintech.TradingServiceClient, timeout time.Duration) *GRPC {
+	return &GRPC{client: client, timeout: timeout}
+}
+
+// Execute выставляет лимитную заявку и ждет события об исполнении
+func (b *GRPC) Execute(ctx context.Context, o *Order) (*Execution, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	side := fintech.Side_BUY
+	if o.Side == deals.Sell {
+		side = fintech.Side_SELL
+	}
+
+	// на проводе цена float64, сама заявка хранит точное значение
+	limit, _ := o.LimitPrice.Float64()
+
+	stream, err := b.client.PlaceOrder(ctx, &fintech.PlaceOrderRequest{
+		Ticker:        o.Ticker,
+		Side:          side,
+		Quantity:      o.Quantity,
+		LimitPrice:    limit,
+		ClientOrderId: o.ClientOrderID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "can't place order")
+	}
+
+	var orderID string
+
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.Wrap(ErrNotFilled, "order stream closed "+orderID)
+		}
+
+		if err != nil {
+			if orderID != "" && ctx.Err() != nil {
+				b.cancel(orderID)
+			}
+
+			return nil, errors.Wrap(err, "can't receive order event "+orderID)
+		}
+
+		order := ev.GetOrder()
+		orderID = order.GetOrderId()
+
+		switch order.GetState() {
+		case fintech.OrderState_FILLED:
+			return execution(order), nil
+		case fintech.OrderState_CANCELED, fintech.OrderState_REJECTED:
+			return nil, errors.Wrap(ErrNotFilled, order.GetState().String()+" "+order.GetReason())
+		case fintech.OrderState_NEW, fintech.OrderState_PARTIALLY_FILLED:
+		}
+	}
+}
+
+// cancel снимает заявку, не дождавшуюся исполнения, уже без контекста вызова
+func (b *GRPC) cancel(orderID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	b.client.CancelOrder(ctx, &fintech.CancelOrderRequest{OrderId: orderID}) // nolint
+}
+
+func execution(order *fintech.Order) *Execution {
+	ex := &Execution{
+		OrderID:  order.GetOrderId(),
+		Quantity: order.GetFilledQuantity(),
+		Price:    decimal.NewFromFloat(order.GetAvgFillPrice()),
+		Ts:       clock.Now(),
+	}
+
+	if fills := order.GetFills(); len(fills) > 0 {
+		ts, err := ptypes.Timestamp(fills[len(fills)-1].GetTs())
+		if err == nil {
+			ex.Ts = ts
+		}
+	}
+
+	return ex
+}
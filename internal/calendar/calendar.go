@@ -0,0 +1,188 @@
+package calendar
+
+import (
+	"authDB/internal/schedule"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Exchange торговое расписание одной биржи
+type Exchange struct {
+	Name string
+	// Zone зона, в которой записаны сессии, праздники и сокращенные дни
+	Zone *time.Location
+	// Tickers тикеры, которые торгуются на бирже
+	Tickers []string
+
+	sessions  *schedule.Schedule
+	shortDays map[string]window
+}
+
+type window struct {
+	from, to time.Time
+}
+
+// Calendar торговые календари бирж. Тикер без биржи и nil календарь торгуются всегда
+type Calendar struct {
+	exchanges map[string]*Exchange
+	tickers   map[string]*Exchange
+}
+
+// exchangeConfig биржа в файле календаря
+type exchangeConfig struct {
+	Zone string `json:"zone"`
+	// Sessions регулярные сессии в формате schedule.Parse, например "weekdays 10:00-18:45"
+	Sessions string `json:"sessions"`
+	// Holidays даты без торгов
+	Holidays []string `json:"holidays"`
+	// ShortDays даты с особыми часами торгов "HH:MM-HH:MM", в том числе рабочие выходные
+	ShortDays map[string]string `json:"short_days"`
+	Tickers   []string          `json:"tickers"`
+}
+
+// Load читает календарь из json файла, см. data/calendar.json
+func Load(path string) (*Calendar, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't read calendar")
+	}
+
+	var cfg struct {
+		Exchanges map[string]exchangeConfig `json:"exchanges"`
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrap(err, "can't parse calendar")
+	}
+
+	c := &Calendar{exchanges: make(map[string]*Exchange), tickers: make(map[string]*Exchange)}
+
+	for name, ec := range cfg.Exchanges {
+		e, err := newExchange(name, ec)
+		if err != nil {
+			return nil, errors.WithMessage(err, "bad exchange "+name)
+		}
+
+		for _, t := range e.Tickers {
+			if other, ok := c.tickers[t]; ok {
+				return nil, errors.New("ticker " + t + " is listed on " + other.Name + " and " + name)
+			}
+
+			c.tickers[t] = e
+		}
+
+		c.exchanges[name] = e
+	}
+
+	return c, nil
+}
+
+func newExchange(name string, ec exchangeConfig) (*Exchange, error) {
+	loc, err := time.LoadLocation(ec.Zone)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad zone")
+	}
+
+	e := &Exchange{Name: name, Zone: loc, shortDays: make(map[string]window)}
+
+	// сокращенные дни исключаются из регулярного расписания и проверяются отдельно
+	exclude := append([]string{}, ec.Holidays...)
+
+	for date, hours := range ec.ShortDays {
+		w, err := parseWindow(date, hours, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		e.shortDays[date] = w
+		exclude = append(exclude, date)
+	}
+
+	if e.sessions, err = schedule.Parse(ec.Sessions, exclude, loc); err != nil {
+		return nil, err
+	}
+
+	for _, t := range ec.Tickers {
+		e.Tickers = append(e.Tickers, strings.ToUpper(t))
+	}
+
+	return e, nil
+}
+
+func parseWindow(date, hours string, loc *time.Location) (window, error) {
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return window{}, errors.New("bad hours of short day " + date)
+	}
+
+	from, err := time.ParseInLocation(schedule.DateLayout+" 15:04", date+" "+parts[0], loc)
+	if err != nil {
+		return window{}, errors.New("bad short day " + date + " " + hours)
+	}
+
+	to, err := time.ParseInLocation(schedule.DateLayout+" 15:04", date+" "+parts[1], loc)
+	if err != nil || !to.After(from) {
+		return window{}, errors.New("bad short day " + date + " " + hours)
+	}
+
+	return window{from: from, to: to}, nil
+}
+
+// Exchange биржа тикера, nil если тикер не привязан к бирже
+func (c *Calendar) Exchange(ticker string) *Exchange {
+	if c == nil {
+		return nil
+	}
+
+	return c.tickers[strings.ToUpper(ticker)]
+}
+
+// Open идут ли торги тикером в момент t
+func (c *Calendar) Open(ticker string, t time.Time) bool {
+	e := c.Exchange(ticker)
+
+	return e == nil || e.Open(t)
+}
+
+// HasSession будут ли торги тикером хоть раз в промежутке [from, to)
+func (c *Calendar) HasSession(ticker string, from, to time.Time) bool {
+	e := c.Exchange(ticker)
+
+	return e == nil || e.HasSession(from, to)
+}
+
+// Open идут ли торги в момент t
+func (e *Exchange) Open(t time.Time) bool {
+	for _, w := range e.shortDays {
+		if !t.Before(w.from) && t.Before(w.to) {
+			return true
+		}
+	}
+
+	return e.sessions.Active(t)
+}
+
+// HasSession будут ли торги хоть раз в промежутке [from, to)
+func (e *Exchange) HasSession(from, to time.Time) bool {
+	if !from.Before(to) {
+		return false
+	}
+
+	if e.Open(from) {
+		return true
+	}
+
+	for _, w := range e.shortDays {
+		if w.from.Before(to) && w.to.After(from) {
+			return true
+		}
+	}
+
+	next, ok := e.sessions.Next(from)
+
+	return ok && next.Before(to)
+}
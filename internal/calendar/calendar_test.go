@@ -0,0 +1,101 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func load(t *testing.T) *Calendar {
+	t.Helper()
+
+	c, err := Load("../../data/calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func zone(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loc
+}
+
+func TestOpen(t *testing.T) {
+	c := load(t)
+	msk, ny := zone(t, "Europe/Moscow"), zone(t, "America/New_York")
+
+	cases := []struct {
+		name   string
+		ticker string
+		at     time.Time
+		want   bool
+	}{
+		{"moex session", "SBER", time.Date(2026, 11, 5, 12, 0, 0, 0, msk), true},
+		{"moex holiday", "SBER", time.Date(2026, 11, 4, 12, 0, 0, 0, msk), false},
+		{"moex weekend", "SBER", time.Date(2026, 10, 17, 12, 0, 0, 0, msk), false},
+		{"moex after close", "SBER", time.Date(2026, 11, 5, 18, 45, 0, 0, msk), false},
+		{"ticker in lower case", "sber", time.Date(2026, 11, 5, 12, 0, 0, 0, msk), true},
+		{"nyse short day morning", "AAPL", time.Date(2026, 11, 27, 12, 0, 0, 0, ny), true},
+		{"nyse short day early close", "AAPL", time.Date(2026, 11, 27, 14, 0, 0, 0, ny), false},
+		{"nyse regular day afternoon", "AAPL", time.Date(2026, 11, 30, 14, 0, 0, 0, ny), true},
+		{"nyse weekend", "AAPL", time.Date(2026, 10, 17, 12, 0, 0, 0, ny), false},
+		{"unknown exchange", "UNKNOWN", time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tc := range cases {
+		if got := c.Open(tc.ticker, tc.at); got != tc.want {
+			t.Errorf("%s: Open(%s, %s) = %v, want %v", tc.name, tc.ticker, tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestUnknownExchange(t *testing.T) {
+	c := load(t)
+	at := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	if e := c.Exchange("UNKNOWN"); e != nil {
+		t.Fatalf("UNKNOWN is listed on %s", e.Name)
+	}
+
+	if !c.HasSession("UNKNOWN", at, at.Add(time.Minute)) {
+		t.Error("ticker without an exchange should always trade")
+	}
+
+	var none *Calendar
+
+	if !none.Open("SBER", at) || !none.HasSession("SBER", at, at.Add(time.Minute)) {
+		t.Error("nil calendar should always trade")
+	}
+}
+
+func TestHasSession(t *testing.T) {
+	c := load(t)
+	msk, ny := zone(t, "Europe/Moscow"), zone(t, "America/New_York")
+
+	cases := []struct {
+		name     string
+		ticker   string
+		from, to time.Time
+		want     bool
+	}{
+		{"moex weekend", "SBER", time.Date(2026, 10, 17, 0, 0, 0, 0, msk), time.Date(2026, 10, 19, 0, 0, 0, 0, msk), false},
+		{"moex weekend and monday", "SBER", time.Date(2026, 10, 17, 0, 0, 0, 0, msk), time.Date(2026, 10, 19, 11, 0, 0, 0, msk), true},
+		{"moex holiday", "SBER", time.Date(2026, 11, 4, 0, 0, 0, 0, msk), time.Date(2026, 11, 5, 0, 0, 0, 0, msk), false},
+		{"nyse short day still open", "AAPL", time.Date(2026, 11, 27, 12, 30, 0, 0, ny), time.Date(2026, 11, 27, 20, 0, 0, 0, ny), true},
+		{"nyse after early close", "AAPL", time.Date(2026, 11, 27, 13, 0, 0, 0, ny), time.Date(2026, 11, 28, 0, 0, 0, 0, ny), false},
+		{"empty range", "SBER", time.Date(2026, 11, 5, 12, 0, 0, 0, msk), time.Date(2026, 11, 5, 12, 0, 0, 0, msk), false},
+	}
+
+	for _, tc := range cases {
+		if got := c.HasSession(tc.ticker, tc.from, tc.to); got != tc.want {
+			t.Errorf("%s: HasSession(%s, %s, %s) = %v, want %v", tc.name, tc.ticker, tc.from, tc.to, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock источник текущего времени
+type Clock interface {
+	Now() time.Time
+}
+
+// System системные часы в UTC
+type System struct{}
+
+// Now ...
+func (System) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Func позволяет подставить функцию вместо часов, например сдвинутое время при отладке окон
+type Func func() time.Time
+
+// Now ...
+func (f Func) Now() time.Time {
+	return f().UTC()
+}
+
+var (
+	mu      sync.RWMutex
+	current Clock = System{}
+)
+
+// Set подменяет часы процесса, по которым идут все сравнения времени
+func Set(c Clock) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current = c
+}
+
+// Now текущее время по часам процесса, всегда в UTC
+func Now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return current.Now()
+}
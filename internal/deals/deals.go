@@ -0,0 +1,73 @@
+package deals
+
+import (
+	"authDB/internal/clock"
+	"authDB/internal/fintech"
+	"authDB/internal/robots"
+	"database/sql"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/shopspring/decimal"
+)
+
+// Стороны сделки
+const (
+	Buy  = "buy"
+	Sell = "sell"
+)
+
+// Причины выхода из позиции
+const (
+	ExitSignal     = "signal"
+	ExitStopLoss   = "stop_loss"
+	ExitTakeProfit = "take_profit"
+	ExitTrailing   = "trailing_stop"
+	ExitPlanYield  = "plan_yield"
+	ExitRiskStop   = "risk_stop"
+	ExitHalt       = "halt"
+)
+
+// Deal сделка робота
+type Deal struct {
+	DealID     int
+	OrderID    string
+	RobotID    int
+	Side       string
+	Price      decimal.Decimal
+	Quantity   int
+	QuoteTs    sql.NullTime
+	ExecutedAt time.Time
+	// ExitReason почему закрыта позиция, у сделок входа пустая
+	ExitReason string
+	// Position сторона позиции, которую сделка открывает или закрывает: long или short
+	Position string
+}
+
+// Deals журнал сделок роботов
+type Deals interface {
+	// Save сохраняет сделку вместе со счетчиками и торговым состоянием робота в одной транзакции
+	// и проводит ее по счету владельца робота
+	Save(d *Deal, rob *robots.Robot, st *robots.State) error
+	GetRobotDeals(robotID int) ([]*Deal, error)
+}
+
+// New формирует сделку по котировке
+func New(robotID int, side string, price decimal.Decimal, quantity int, quote *fintech.PriceResponse) *Deal {
+	d := &Deal{
+		RobotID:    robotID,
+		Side:       side,
+		Price:      price,
+		Quantity:   quantity,
+		ExecutedAt: clock.Now(),
+	}
+
+	if quote.GetTs() != nil {
+		ts, err := ptypes.Timestamp(quote.GetTs())
+		if err == nil {
+			d.QuoteTs = sql.NullTime{Time: ts, Valid: true}
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"authDB/internal/broker"
+	"authDB/internal/deals"
+	"authDB/internal/pricefeed"
+	"testing"
+	"time"
+)
+
+// TestSupervisorTradesAgainstPriceFeed гоняет супервизор против учебной биржи в памяти:
+// котировки идут по скрипту, заявки исполняет ее книга заявок через настоящий grpc брокер
+func TestSupervisorTradesAgainstPriceFeed(t *testing.T) {
+	const id = 1
+
+	cfg := pricefeed.DefaultConfig()
+	cfg.Tickers["SBER"] = pricefeed.TickerConfig{
+		Mode:     pricefeed.ModeScript,
+		Interval: pricefeed.Duration{Duration: 20 * time.Millisecond},
+		Spread:   0.1,
+		Prices:   []float64{9.8, 9.8, 11, 12.2, 12.2, 11},
+	}
+
+	client, stopFeed, err := pricefeed.Dial(pricefeed.NewServer(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer stopFeed()
+
+	db := newRepos(liveRobot(id, "10", "12"))
+	s := db.supervisor(client, broker.NewGRPC(client, waitTimeout))
+	stop := running(s)
+
+	eventually(t, "a round trip", func() bool { return db.deals.count() >= 2 })
+	stop()
+
+	ds, _ := db.deals.GetRobotDeals(id)
+
+	if ds[0].Side != deals.Buy || ds[1].Side != deals.Sell {
+		t.Fatalf("deals %s then %s, want buy then sell", ds[0].Side, ds[1].Side)
+	}
+
+	if ds[0].OrderID == "" || ds[1].OrderID == "" {
+		t.Fatal("deals are not linked to exchange orders")
+	}
+
+	// покупка по 9.85 не дороже порога 10, продажа по 12.15 не дешевле порога 12
+	if ds[0].Price.GreaterThan(ds[1].Price) {
+		t.Fatalf("bought at %v and sold at %v", ds[0].Price, ds[1].Price)
+	}
+}
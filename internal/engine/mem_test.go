@@ -0,0 +1,393 @@
+package engine
+
+import (
+	"authDB/internal/accounts"
+	"authDB/internal/broker"
+	"authDB/internal/clock"
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"authDB/internal/halt"
+	"authDB/internal/limits"
+	"authDB/internal/market"
+	"authDB/internal/robots"
+	"authDB/pkg/logger"
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const waitTimeout = 5 * time.Second
+
+// memRobots хранилище роботов в памяти, реализует только то, чем пользуется движок
+type memRobots struct {
+	robots.Robots
+
+	mu     sync.Mutex
+	robots map[int]*robots.Robot
+	// statusErr ошибка записи статуса, как при недоступной базе
+	statusErr error
+}
+
+func newMemRobots(rbts ...*robots.Robot) *memRobots {
+	m := &memRobots{robots: make(map[int]*robots.Robot)}
+	for _, rob := range rbts {
+		m.robots[rob.RobotID] = rob
+	}
+
+	return m
+}
+
+func (m *memRobots) GetRobot(id int) (*robots.Robot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rob, ok := m.robots[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	r := *rob
+
+	return &r, nil
+}
+
+func (m *memRobots) GetAllNonDeletedRobots() ([]*robots.Robot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rbts := make([]*robots.Robot, 0, len(m.robots))
+	for _, rob := range m.robots {
+		r := *rob
+		rbts = append(rbts, &r)
+	}
+
+	return rbts, nil
+}
+
+func (m *memRobots) change(id int, f func(rob *robots.Robot)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rob, ok := m.robots[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	f(rob)
+
+	return nil
+}
+
+func (m *memRobots) DeactivateRobot(id int, reason string) error {
+	return m.change(id, func(rob *robots.Robot) {
+		rob.IsActive = false
+		rob.ActivatedAt = sql.NullTime{}
+		rob.DeactivatedAt = sql.NullTime{Time: clock.Now(), Valid: true}
+		rob.Status, rob.StatusReason = robots.StatusDraft, reason
+	})
+}
+
+func (m *memRobots) SetDegraded(id int, degraded bool) error {
+	return m.change(id, func(rob *robots.Robot) { rob.IsDegraded = degraded })
+}
+
+// SetStatus проверяет переход по той же таблице, что и запрос в базе
+func (m *memRobots) SetStatus(id int, to robots.Status, reason string) error {
+	m.mu.Lock()
+	err := m.statusErr
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	var bad error
+
+	err = m.change(id, func(rob *robots.Robot) {
+		if bad = robots.CheckTransition(rob.Status, to); bad == nil {
+			rob.Status, rob.StatusReason = to, reason
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return bad
+}
+
+func (m *memRobots) status(id int) robots.Status {
+	rob, _ := m.GetRobot(id) // nolint
+
+	return rob.Status
+}
+
+func (m *memRobots) SetPlanReached(id int, outcome string) error {
+	return m.change(id, func(rob *robots.Robot) {
+		rob.PlanOutcome = outcome
+		rob.PlanReachedAt = sql.NullTime{Time: clock.Now(), Valid: true}
+	})
+}
+
+// memStates хранилище торговых состояний в памяти
+type memStates struct {
+	mu     sync.Mutex
+	states map[int]robots.State
+	// getErr ошибка чтения состояния, gets сколько раз состояние читали
+	getErr error
+	gets   int
+}
+
+func newMemStates() *memStates {
+	return &memStates{states: make(map[int]robots.State)}
+}
+
+func (m *memStates) SaveState(st *robots.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[st.RobotID] = *st
+
+	return nil
+}
+
+func (m *memStates) GetState(robotID int) (*robots.State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gets++
+
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	st, ok := m.states[robotID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &st, nil
+}
+
+// memDeals сохраняет сделку вместе с состоянием робота, как одна транзакция в базе
+type memDeals struct {
+	states *memStates
+
+	mu    sync.Mutex
+	deals []*deals.Deal
+	// failures сколько следующих записей завершатся ошибкой failErr, по умолчанию как при недоступной базе
+	failures int
+	failErr  error
+}
+
+func (m *memDeals) Save(d *deals.Deal, rob *robots.Robot, st *robots.State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failures > 0 {
+		m.failures--
+
+		if m.failErr != nil {
+			return m.failErr
+		}
+
+		return errors.New("database is down")
+	}
+
+	d.DealID = len(m.deals) + 1
+	m.deals = append(m.deals, d)
+
+	return m.states.SaveState(st)
+}
+
+func (m *memDeals) GetRobotDeals(robotID int) ([]*deals.Deal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ds []*deals.Deal
+
+	for _, d := range m.deals {
+		if d.RobotID == robotID {
+			ds = append(ds, d)
+		}
+	}
+
+	return ds, nil
+}
+
+func (m *memStates) reads() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.gets
+}
+
+func (m *memDeals) fail(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures = n
+}
+
+func (m *memDeals) left() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.failures
+}
+
+func (m *memDeals) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.deals)
+}
+
+// noLimits лимиты не заданы
+type noLimits struct{}
+
+func (noLimits) GetLimits(userID int) (*limits.Limits, error) {
+	return &limits.Limits{UserID: userID}, nil
+}
+
+func (noLimits) SetLimits(l *limits.Limits) error {
+	return nil
+}
+
+func (noLimits) GetUsage(userID int) (*limits.Usage, error) {
+	return &limits.Usage{}, nil
+}
+
+// noHalt торговля не остановлена
+type noHalt struct{}
+
+func (noHalt) Get() (*halt.State, error) {
+	return &halt.State{}, nil
+}
+
+func (noHalt) Set(st *halt.State) error {
+	return nil
+}
+
+// richAccounts у каждого пользователя хватает денег на любую сделку
+type richAccounts struct {
+	accounts.Storage
+}
+
+func (richAccounts) GetAccount(userID int) (*accounts.Account, error) {
+	return &accounts.Account{UserID: userID, Cash: decimal.NewFromInt(1000000)}, nil
+}
+
+// fakeBroker исполняет заявку сразу по лимитной цене, повтор с тем же ClientOrderID возвращает первое исполнение
+type fakeBroker struct {
+	mu         sync.Mutex
+	orders     []*broker.Order
+	executions map[string]*broker.Execution
+}
+
+func (b *fakeBroker) Execute(ctx context.Context, o *broker.Order) (*broker.Execution, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ex, ok := b.executions[o.ClientOrderID]; ok {
+		return ex, nil
+	}
+
+	if b.executions == nil {
+		b.executions = make(map[string]*broker.Execution)
+	}
+
+	ex := &broker.Execution{OrderID: o.ClientOrderID, Quantity: o.Quantity, Price: o.LimitPrice, Ts: clock.Now()}
+	b.orders = append(b.orders, o)
+	b.executions[o.ClientOrderID] = ex
+
+	return ex, nil
+}
+
+func (b *fakeBroker) placed() []*broker.Order {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]*broker.Order{}, b.orders...)
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) Notify(rob *robots.Robot) {}
+
+// repos общие для нескольких супервизоров хранилища, как база между рестартами процесса
+type repos struct {
+	robots *memRobots
+	states *memStates
+	deals  *memDeals
+}
+
+func newRepos(rbts ...*robots.Robot) *repos {
+	states := newMemStates()
+
+	return &repos{robots: newMemRobots(rbts...), states: states, deals: &memDeals{states: states}}
+}
+
+func (r *repos) supervisor(client fintech.TradingServiceClient, b broker.Broker) *Supervisor {
+	log := logger.NewNop()
+	hub := market.NewHub(client, log, market.Config{MinBackoff: 10 * time.Millisecond, MaxBackoff: 100 * time.Millisecond})
+
+	return NewSupervisor(log, r.robots, r.deals, r.states, noLimits{}, noHalt{}, richAccounts{}, hub, b, nopNotifier{},
+		Config{DegradedAfter: time.Minute, SyncInterval: 10 * time.Millisecond})
+}
+
+// running запускает супервизор, возвращенная функция останавливает его и ждет остановки воркеров
+func running(s *Supervisor) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// liveRobot робот threshold, который сейчас внутри окна плана
+func liveRobot(id int, buy, sell string) *robots.Robot {
+	now := clock.Now()
+
+	return &robots.Robot{
+		RobotID:     id,
+		OwnerUserID: 1,
+		IsActive:    true,
+		Ticker:      "SBER",
+		BuyPrice:    decimal.RequireFromString(buy),
+		SellPrice:   decimal.RequireFromString(sell),
+		PlanStart:   sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+		PlanEnd:     sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+		Quantity:    1,
+		Direction:   robots.DirectionLong,
+		OnPlanYield: robots.PlanNotify,
+		Status:      robots.StatusRunning,
+	}
+}
+
+// eventually ждет выполнения условия
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %s", what)
+}
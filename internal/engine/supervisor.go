@@ -0,0 +1,343 @@
+package engine
+
+import (
+	"authDB/internal/accounts"
+	"authDB/internal/broker"
+	"authDB/internal/calendar"
+	"authDB/internal/clock"
+	"authDB/internal/deals"
+	"authDB/internal/halt"
+	"authDB/internal/limits"
+	"authDB/internal/market"
+	"authDB/internal/robots"
+	"authDB/pkg/logger"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Notifier получает актуальное состояние робота после каждой сделки
+type Notifier interface {
+	Notify(rob *robots.Robot)
+}
+
+// Config настройки движка
+type Config struct {
+	// DegradedAfter через сколько без котировок робот помечается деградировавшим
+	DegradedAfter time.Duration
+	// SyncInterval как часто сверять запущенные воркеры с базой
+	SyncInterval time.Duration
+	// Calendar торговые часы бирж, вне их роботы не открывают позиции; nil разрешает торговать всегда
+	Calendar *calendar.Calendar
+}
+
+// Supervisor владеет воркерами роботов: по одному отменяемому воркеру на робота
+type Supervisor struct {
+	logger       logger.Logger
+	repoRobot    robots.Robots
+	repoDeal     deals.Deals
+	repoState    robots.States
+	repoLimits   limits.Storage
+	repoHalt     halt.Storage
+	repoAccounts accounts.Storage
+	hub          *market.Hub
+	broker       broker.Broker
+	notifier     Notifier
+	cfg          Config
+
+	mu      sync.Mutex
+	workers map[int]*worker
+	// failed роботы, чей воркер не смог стартовать; до Reload они не перезапускаются,
+	// даже если статус failed не удалось записать в базу
+	failed map[int]bool
+
+	haltMu sync.RWMutex
+	halt   halt.State
+}
+
+// NewSupervisor ...
+func NewSupervisor(logger logger.Logger, repoRobot robots.Robots, repoDeal deals.Deals, repoState robots.States,
+	repoLimits limits.Storage, repoHalt halt.Storage, repoAccounts accounts.Storage, hub *market.Hub, broker broker.Broker,
+	notifier Notifier, cfg Config) *Supervisor {
+	return &Supervisor{
+		logger:       logger,
+		repoRobot:    repoRobot,
+		repoDeal:     repoDeal,
+		repoState:    repoState,
+		repoLimits:   repoLimits,
+		repoHalt:     repoHalt,
+		repoAccounts: repoAccounts,
+		hub:          hub,
+		broker:       broker,
+		notifier:     notifier,
+		cfg:          cfg,
+		workers:      make(map[int]*worker),
+		failed:       make(map[int]bool),
+	}
+}
+
+// Run периодически сверяет воркеры с базой, пока не отменен контекст
+func (s *Supervisor) Run(ctx context.Context) {
+	t := time.NewTicker(s.cfg.SyncInterval)
+	defer t.Stop()
+
+	for {
+		s.sync()
+
+		select {
+		case <-ctx.Done():
+			s.stopAll()
+
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (s *Supervisor) sync() {
+	s.loadHalt()
+
+	rbts, err := s.repoRobot.GetAllNonDeletedRobots()
+	if err != nil {
+		s.logger.Errorf("failed to get robots on sync %s", err)
+
+		return
+	}
+
+	alive := make(map[int]bool, len(rbts))
+
+	for _, rob := range rbts {
+		alive[rob.RobotID] = true
+
+		s.apply(rob)
+	}
+
+	s.mu.Lock()
+	var gone []int
+
+	for id := range s.workers {
+		if !alive[id] {
+			gone = append(gone, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range gone {
+		s.Stop(id)
+	}
+}
+
+// Reload перечитывает робота и сразу запускает, обновляет или останавливает его воркер.
+// Робот, чей воркер не смог стартовать, после Reload снова запускается
+func (s *Supervisor) Reload(id int) {
+	s.mu.Lock()
+	delete(s.failed, id)
+	s.mu.Unlock()
+
+	rob, err := s.repoRobot.GetRobot(id)
+	if err != nil {
+		s.Stop(id)
+
+		return
+	}
+
+	s.apply(rob)
+}
+
+func (s *Supervisor) apply(rob *robots.Robot) {
+	now := clock.Now()
+
+	switch {
+	case shouldRun(rob, now):
+		s.start(rob)
+	case rob.Status.IsLive() && windowPassed(rob, now):
+		s.Stop(rob.RobotID)
+		s.setStatus(rob.RobotID, robots.StatusFinished, "plan window ended")
+	case rob.Status == robots.StatusRunning:
+		s.Stop(rob.RobotID)
+		s.setStatus(rob.RobotID, robots.StatusScheduled, "waiting for trading session")
+	default:
+		s.Stop(rob.RobotID)
+	}
+}
+
+// Halt останавливает открытие позиций всеми роботами, с flatten они закрывают и открытые
+func (s *Supervisor) Halt(reason string, flatten bool) (halt.State, error) {
+	return s.setHalt(halt.State{Halted: true, Flatten: flatten, Reason: reason})
+}
+
+// Resume снимает глобальную остановку торговли
+func (s *Supervisor) Resume() (halt.State, error) {
+	return s.setHalt(halt.State{})
+}
+
+// Halted текущая глобальная остановка торговли
+func (s *Supervisor) Halted() halt.State {
+	s.haltMu.RLock()
+	defer s.haltMu.RUnlock()
+
+	return s.halt
+}
+
+func (s *Supervisor) setHalt(st halt.State) (halt.State, error) {
+	if err := s.repoHalt.Set(&st); err != nil {
+		return halt.State{}, err
+	}
+
+	s.haltMu.Lock()
+	s.halt = st
+	s.haltMu.Unlock()
+
+	s.logger.Warnw("trading halt changed", "halted", st.Halted, "flatten", st.Flatten, "reason", st.Reason)
+
+	return st, nil
+}
+
+// loadHalt перечитывает остановку из базы, чтобы она пережила рестарт
+func (s *Supervisor) loadHalt() {
+	st, err := s.repoHalt.Get()
+	if err != nil {
+		s.logger.Errorf("failed to get trading halt %s", err)
+
+		return
+	}
+
+	s.haltMu.Lock()
+	s.halt = *st
+	s.haltMu.Unlock()
+}
+
+// notify отправляет снимок робота с отметкой об остановке торговли
+func (s *Supervisor) notify(rob robots.Robot) {
+	rob.TradingHalted = s.Halted().Halted
+	s.notifier.Notify(&rob)
+}
+
+func (s *Supervisor) setStatus(id int, to robots.Status, reason string) {
+	err := s.repoRobot.SetStatus(id, to, reason)
+	if err != nil {
+		s.logger.Errorf("failed to move robotID:%v to %s %s", id, to, err)
+	}
+}
+
+func (s *Supervisor) start(rob *robots.Robot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.workers[rob.RobotID]; ok {
+		w.update(rob)
+
+		return
+	}
+
+	if s.failed[rob.RobotID] {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := newWorker(s, rob, cancel)
+	s.workers[rob.RobotID] = w
+
+	go func() {
+		w.run(ctx)
+		s.finished(w)
+	}()
+}
+
+// Stop останавливает воркер робота и дожидается его завершения
+func (s *Supervisor) Stop(id int) {
+	s.mu.Lock()
+	w, ok := s.workers[id]
+
+	if ok {
+		delete(s.workers, id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	w.cancel()
+	<-w.done
+}
+
+func (s *Supervisor) stopAll() {
+	s.mu.Lock()
+	ids := make([]int, 0, len(s.workers))
+
+	for id := range s.workers {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		s.Stop(id)
+	}
+}
+
+// startFailed переводит робота, чей воркер не смог стартовать, в failed и запоминает это,
+// чтобы sync не запускал его заново каждый раз
+func (s *Supervisor) startFailed(id int, err error) {
+	s.mu.Lock()
+	s.failed[id] = true
+	s.mu.Unlock()
+
+	s.setStatus(id, robots.StatusFailed, err.Error())
+}
+
+func (s *Supervisor) finished(w *worker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.workers[w.robotID] == w {
+		delete(s.workers, w.robotID)
+	}
+
+	w.cancel()
+}
+
+// Statuses состояние всех запущенных воркеров
+func (s *Supervisor) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.workers))
+	for _, w := range s.workers {
+		statuses = append(statuses, w.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].RobotID < statuses[j].RobotID })
+
+	return statuses
+}
+
+// Status состояние воркера робота, если он запущен
+func (s *Supervisor) Status(id int) (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[id]
+	if !ok {
+		return Status{}, false
+	}
+
+	return w.status(), true
+}
+
+// inWindow проверяет, что сейчас идет торговая сессия робота внутри его планового окна
+func inWindow(rob *robots.Robot, now time.Time) bool {
+	return rob.InSession(now)
+}
+
+// windowPassed проверяет, что плановое окно робота уже закончилось
+func windowPassed(rob *robots.Robot, now time.Time) bool {
+	return !now.Before(rob.PlanEnd.Time)
+}
+
+// shouldRun воркер нужен активированному роботу в его сессию, на паузе воркер держит позицию.
+// Между сессиями робот остается scheduled и запускается сам, позиция сохраняется до следующей сессии
+func shouldRun(rob *robots.Robot, now time.Time) bool {
+	return rob.Status.IsLive() && inWindow(rob, now)
+}
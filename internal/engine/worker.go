@@ -0,0 +1,569 @@
+package engine
+
+import (
+	"authDB/internal/accounts"
+	"authDB/internal/broker"
+	"authDB/internal/clock"
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"authDB/internal/limits"
+	"authDB/internal/robots"
+	"authDB/internal/trading"
+	"context"
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	feedCheckInterval = time.Second
+
+	// saveRetries сколько раз повторить запись исполненной сделки, прежде чем отложить ее до следующей котировки
+	saveRetries = 3
+)
+
+// saveRetryDelay первая пауза между повторами записи сделки, дальше она удваивается
+var saveRetryDelay = 200 * time.Millisecond
+
+// Status живое состояние воркера робота
+type Status struct {
+	RobotID    int
+	Ticker     string
+	Strategy   string
+	Status     robots.Status
+	Side       string
+	EntryPrice decimal.Decimal
+	Degraded   bool
+	DealsCount int
+	FactYield  decimal.Decimal
+	StartedAt  time.Time
+	LastTickAt time.Time `json:",omitempty"`
+}
+
+type worker struct {
+	s       *Supervisor
+	robotID int
+	cancel  context.CancelFunc
+	done    chan struct{}
+	updates chan *robots.Robot
+
+	// поля ниже меняет только горутина воркера, mu защищает их чтение из status
+	mu         sync.Mutex
+	robot      robots.Robot
+	trader     *trading.Trader
+	startedAt  time.Time
+	lastTickAt time.Time
+}
+
+func newWorker(s *Supervisor, rob *robots.Robot, cancel context.CancelFunc) *worker {
+	return &worker{
+		s:         s,
+		robotID:   rob.RobotID,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		updates:   make(chan *robots.Robot, 1),
+		robot:     *rob,
+		startedAt: clock.Now(),
+	}
+}
+
+// update передает воркеру новые параметры робота, старые непримененные отбрасываются
+func (w *worker) update(rob *robots.Robot) {
+	select {
+	case <-w.updates:
+	default:
+	}
+
+	w.updates <- rob
+}
+
+func (w *worker) status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	st := Status{
+		RobotID:    w.robot.RobotID,
+		Ticker:     w.robot.Ticker,
+		Strategy:   w.robot.Strategy,
+		Status:     w.robot.Status,
+		Degraded:   w.robot.IsDegraded,
+		DealsCount: w.robot.DealsCount,
+		FactYield:  w.robot.FactYield,
+		StartedAt:  w.startedAt,
+		LastTickAt: w.lastTickAt,
+	}
+
+	if w.trader != nil {
+		st.Side = w.trader.Side.String()
+		st.EntryPrice = w.trader.EntryPrice
+	}
+
+	return st
+}
+
+func (w *worker) run(ctx context.Context) { // nolint
+	defer close(w.done)
+
+	log := w.s.logger
+
+	trader, err := trading.New(&w.robot)
+	if err != nil {
+		log.Errorf("failed to create strategy for robotID:%v %s", w.robotID, err)
+		w.s.startFailed(w.robotID, err)
+
+		return
+	}
+
+	w.mu.Lock()
+	w.trader = trader
+	w.mu.Unlock()
+
+	if err := w.restore(); err != nil {
+		log.Errorf("failed to restore state of robotID:%v %s", w.robotID, err)
+		w.s.startFailed(w.robotID, err)
+
+		return
+	}
+
+	defer w.saveState()
+
+	if w.robot.Status == robots.StatusScheduled {
+		err = w.s.repoRobot.SetStatus(w.robotID, robots.StatusRunning, "plan window started")
+		if err != nil {
+			log.Errorf("failed to start robotID:%v %s", w.robotID, err)
+
+			return
+		}
+
+		w.mu.Lock()
+		w.robot.Status = robots.StatusRunning
+		w.mu.Unlock()
+	}
+
+	sub := w.s.hub.Subscribe(w.robot.Ticker)
+	defer func() { sub.Close() }()
+
+	feedCheck := time.NewTicker(feedCheckInterval)
+	defer feedCheck.Stop()
+
+	log.Debugf("stream is starting with ticker:%s and robotID:%v", w.robot.Ticker, w.robotID)
+
+	for {
+		if now := clock.Now(); !inWindow(&w.robot, now) {
+			log.Debugf("stream has ended with ticker:%s and robotID:%v", w.robot.Ticker, w.robotID)
+
+			if windowPassed(&w.robot, now) {
+				w.s.setStatus(w.robotID, robots.StatusFinished, "plan window ended")
+			} else if w.robot.Status == robots.StatusRunning {
+				w.s.setStatus(w.robotID, robots.StatusScheduled, "trading session ended")
+			}
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Debugf("robot worker was stopped with ticker:%s and robotID:%v", w.robot.Ticker, w.robotID)
+
+			return
+		case rob := <-w.updates:
+			ticker := w.robot.Ticker
+
+			if err := w.apply(rob); err != nil {
+				log.Errorf("failed to update robotID:%v %s", w.robotID, err)
+
+				continue
+			}
+
+			if ticker != w.robot.Ticker {
+				sub.Close()
+				sub = w.s.hub.Subscribe(w.robot.Ticker)
+			}
+		case <-feedCheck.C:
+			if !w.robot.IsDegraded && w.s.hub.DownFor(w.robot.Ticker) > w.s.cfg.DegradedAfter {
+				w.setDegraded(true)
+			}
+		case data, ok := <-sub.C:
+			if !ok {
+				log.Debugf("price stream was closed with ticker:%s and robotID:%v", w.robot.Ticker, w.robotID)
+
+				return
+			}
+
+			if w.robot.IsDegraded {
+				w.setDegraded(false)
+			}
+
+			// на паузе котировки пропускаются, позиция и счетчики остаются как есть
+			if w.robot.Status == robots.StatusPaused {
+				continue
+			}
+
+			if w.onTick(ctx, data) {
+				return
+			}
+		}
+	}
+}
+
+// apply применяет новые параметры робота, сохраняя позицию и счетчики
+func (w *worker) apply(rob *robots.Robot) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.trader.Configure(rob); err != nil {
+		return err
+	}
+
+	w.robot.Ticker = rob.Ticker
+	w.robot.BuyPrice = rob.BuyPrice
+	w.robot.SellPrice = rob.SellPrice
+	w.robot.PlanStart = rob.PlanStart
+	w.robot.PlanEnd = rob.PlanEnd
+	w.robot.PlanYield = rob.PlanYield
+	w.robot.Quantity = rob.Quantity
+	w.robot.StopLoss = rob.StopLoss
+	w.robot.TakeProfit = rob.TakeProfit
+	w.robot.TrailingStop = rob.TrailingStop
+	w.robot.OnPlanYield = rob.OnPlanYield
+	w.robot.PlanOutcome = rob.PlanOutcome
+	w.robot.PlanReachedAt = rob.PlanReachedAt
+	w.robot.MaxLoss = rob.MaxLoss
+	w.robot.MaxDrawdown = rob.MaxDrawdown
+	w.robot.Direction = rob.Direction
+	w.robot.Schedule = rob.Schedule
+	w.robot.ScheduleExclude = rob.ScheduleExclude
+	w.robot.Strategy = rob.Strategy
+	w.robot.StrategyParams = rob.StrategyParams
+	w.robot.ActivatedAt = rob.ActivatedAt
+	w.robot.DeactivatedAt = rob.DeactivatedAt
+	w.robot.Status = rob.Status
+
+	return nil
+}
+
+// onTick выставляет заявку по сигналу стратегии и проверяет плановую доходность.
+// Возвращает true, если воркер должен остановиться
+func (w *worker) onTick(ctx context.Context, data *fintech.PriceResponse) bool {
+	w.mu.Lock()
+	w.lastTickAt = clock.Now()
+	pending := w.trader.Pending
+	w.mu.Unlock()
+
+	// заявка не записана сделкой после сбоя или рестарта: сначала повторяем ее, биржа вернет то же исполнение
+	if pending != nil {
+		w.execute(ctx, data, trading.FillFor(pending))
+
+		return false
+	}
+
+	w.mu.Lock()
+	breach := w.trader.Risk(data)
+	w.mu.Unlock()
+
+	if breach != "" {
+		return w.riskStop(ctx, data, breach)
+	}
+
+	halted := w.s.Halted()
+	if halted.Halted && halted.Flatten {
+		if fill := w.trader.Close(data, deals.ExitHalt); fill != nil {
+			w.execute(ctx, data, fill)
+		}
+
+		return false
+	}
+
+	w.mu.Lock()
+	highWater := w.trader.HighWater
+	fill := w.trader.Signal(data)
+	w.mu.Unlock()
+
+	switch {
+	// при глобальной остановке новые позиции не открываются, выходы работают
+	case fill != nil && fill.Open && halted.Halted:
+	case fill != nil && fill.Open && !w.allowEntry(fill):
+	case fill != nil:
+		w.execute(ctx, data, fill)
+	case !w.trader.HighWater.Equal(highWater) && w.robot.TrailingStop.Enabled():
+		// новая лучшая цена сохраняется сразу, чтобы трейлинг-стоп пережил рестарт
+		w.saveState()
+	}
+
+	return w.checkPlan(ctx, data)
+}
+
+// allowEntry проверяет торговые часы биржи, риск-лимиты и деньги владельца перед открытием позиции.
+// Short тоже требует денег на сумму входа в качестве обеспечения
+func (w *worker) allowEntry(fill *trading.Fill) bool {
+	if !w.s.cfg.Calendar.Open(w.robot.Ticker, w.lastTickAt) {
+		w.s.logger.Debugw("entry was skipped, market is closed", "robotID", w.robotID, "ticker", w.robot.Ticker)
+
+		return false
+	}
+
+	l, err := w.s.repoLimits.GetLimits(w.robot.OwnerUserID)
+	if err == nil {
+		var u *limits.Usage
+
+		u, err = w.s.repoLimits.GetUsage(w.robot.OwnerUserID)
+		if err == nil {
+			err = l.CheckEntry(u, w.robot.Ticker, fill.Amount())
+		}
+	}
+
+	if err == nil {
+		var a *accounts.Account
+
+		a, err = w.s.repoAccounts.GetAccount(w.robot.OwnerUserID)
+		if err == nil {
+			err = a.CheckFunds(fill.Amount())
+		}
+	}
+
+	if err != nil {
+		w.s.logger.Warnw("entry was not allowed", "robotID", w.robotID, "userID", w.robot.OwnerUserID, "err", err)
+
+		return false
+	}
+
+	return true
+}
+
+// execute выставляет заявку; сделка записывается и меняет позицию только после подтверждения
+// исполнения биржей. Заявка сохраняется до отправки и остается в состоянии, пока сделка не записана,
+// поэтому повтор после сбоя или рестарта идет с тем же ClientOrderID и биржа не исполняет ее дважды
+func (w *worker) execute(ctx context.Context, data *fintech.PriceResponse, fill *trading.Fill) bool {
+	w.mu.Lock()
+	if w.trader.Pending == nil {
+		w.trader.Pending = fill.Order(strconv.Itoa(w.robotID) + "-" + strconv.Itoa(w.trader.OrderSeq))
+	}
+
+	order := w.trader.Pending
+	st := w.trader.State(w.robotID)
+	w.mu.Unlock()
+
+	if err := w.s.repoState.SaveState(st); err != nil {
+		w.s.logger.Errorf("failed to save order %s of robotID:%v %s", order.ClientOrderID, w.robotID, err)
+
+		return false
+	}
+
+	fill = trading.FillFor(order)
+
+	ex, err := w.s.broker.Execute(ctx, &broker.Order{
+		ClientOrderID: order.ClientOrderID,
+		Ticker:        w.robot.Ticker,
+		Side:          fill.Side,
+		Quantity:      int64(fill.Quantity),
+		LimitPrice:    fill.Price,
+	})
+	if err != nil {
+		w.s.logger.Warnw("order was not executed", "robotID", w.robotID, "side", fill.Side, "reason", fill.Reason, "price", fill.Price, "err", err)
+
+		// заявку сняли или отклонили, сделки не будет: следующая пойдет под новым номером.
+		// При других ошибках исход неизвестен, заявка повторится на следующей котировке
+		if errors.Cause(err) == broker.ErrNotFilled {
+			w.mu.Lock()
+			w.trader.Pending = nil
+			w.trader.OrderSeq++
+			w.mu.Unlock()
+
+			w.saveState()
+		}
+
+		return false
+	}
+
+	fill.Price = ex.Price
+	fill.Quantity = int(ex.Quantity)
+
+	w.mu.Lock()
+	next := *w.trader
+	rob := w.robot
+	w.mu.Unlock()
+
+	next.Execute(fill, ex.Ts)
+	next.Pending = nil
+	next.OrderSeq++
+	rob.DealsCount = next.DealsCount
+	rob.FactYield = next.FactYield
+
+	d := deals.New(w.robotID, fill.Side, fill.Price, fill.Quantity, data)
+	d.OrderID = ex.OrderID
+	d.ExitReason = fill.Reason
+	d.Position = fill.Position.String()
+	d.ExecutedAt = ex.Ts
+
+	if err := w.saveDeal(ctx, d, &rob, next.State(w.robotID)); err != nil {
+		// позиция в памяти не меняется, заявка остается в состоянии и повторится на следующей котировке
+		w.s.logger.Errorf("failed to save %s deal of order %s in stream robotID:%v %s", fill.Side, order.ClientOrderID, w.robotID, err)
+
+		return false
+	}
+
+	w.mu.Lock()
+	*w.trader = next
+	w.robot.DealsCount = rob.DealsCount
+	w.robot.FactYield = rob.FactYield
+	w.mu.Unlock()
+
+	if !fill.Open {
+		w.s.notify(w.robot)
+	}
+
+	return true
+}
+
+// saveDeal записывает исполненную сделку, повторяя запись при сбоях: исполнение на бирже уже случилось
+func (w *worker) saveDeal(ctx context.Context, d *deals.Deal, rob *robots.Robot, st *robots.State) error {
+	delay := saveRetryDelay
+
+	for i := 0; ; i++ {
+		err := w.s.repoDeal.Save(d, rob, st)
+		// нехватку денег повтор не исправит, сделка запишется после пополнения счета
+		if err == nil || i == saveRetries || errors.Cause(err) == accounts.ErrInsufficientFunds {
+			return err
+		}
+
+		w.s.logger.Warnw("deal was not saved, retrying", "robotID", w.robotID, "orderID", d.OrderID, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// riskStop закрывает позицию и деактивирует робота, превысившего лимит убытка или просадки.
+// Возвращает true, если воркер должен остановиться
+func (w *worker) riskStop(ctx context.Context, data *fintech.PriceResponse, breach string) bool {
+	// если закрыть позицию не удалось, попробуем на следующей котировке
+	if fill := w.trader.Close(data, deals.ExitRiskStop); fill != nil && !w.execute(ctx, data, fill) {
+		return false
+	}
+
+	reason := "risk-stopped: " + breach
+
+	if err := w.s.repoRobot.DeactivateRobot(w.robotID, reason); err != nil {
+		w.s.logger.Errorf("failed to deactivate robotID:%v %s", w.robotID, err)
+	}
+
+	w.s.logger.Warnw("robot was risk-stopped", "robotID", w.robotID, "factYield", w.trader.FactYield, "limit", breach)
+
+	w.mu.Lock()
+	w.robot.Status = robots.StatusDraft
+	w.robot.StatusReason = reason
+	w.mu.Unlock()
+
+	w.s.notify(w.robot)
+
+	return true
+}
+
+// checkPlan выполняет действие робота при достижении плановой доходности, решение принимает трейдер,
+// как и в бэктесте. Возвращает true, если воркер должен остановиться
+func (w *worker) checkPlan(ctx context.Context, data *fintech.PriceResponse) bool {
+	w.mu.Lock()
+	action, fill := w.trader.Plan(data)
+	w.mu.Unlock()
+
+	if action == "" {
+		return false
+	}
+
+	// если закрыть позицию не удалось, попробуем на следующей котировке
+	if fill != nil && !w.execute(ctx, data, fill) {
+		return false
+	}
+
+	outcome := action.Outcome()
+
+	if err := w.s.repoRobot.SetPlanReached(w.robotID, outcome); err != nil {
+		w.s.logger.Errorf("failed to save plan outcome of robotID:%v %s", w.robotID, err)
+
+		return false
+	}
+
+	w.mu.Lock()
+	w.trader.PlanDone()
+	w.robot.PlanOutcome = outcome
+	w.robot.PlanReachedAt = sql.NullTime{Time: clock.Now(), Valid: true}
+	w.mu.Unlock()
+
+	w.s.logger.Warnw("robot reached plan yield", "robotID", w.robotID, "factYield", w.trader.FactYield, "outcome", outcome)
+
+	const reason = "plan yield reached"
+
+	switch action {
+	case robots.PlanDeactivate:
+		// так же, как riskStop: робот снимается с активации и получает deactivated_at
+		if err := w.s.repoRobot.DeactivateRobot(w.robotID, reason); err != nil {
+			w.s.logger.Errorf("failed to deactivate robotID:%v %s", w.robotID, err)
+		}
+
+		w.mu.Lock()
+		w.robot.Status = robots.StatusDraft
+		w.robot.StatusReason = reason
+		w.mu.Unlock()
+	case robots.PlanClose:
+		w.s.setStatus(w.robotID, robots.StatusFinished, reason)
+	case robots.PlanNotify:
+	}
+
+	w.s.notify(w.robot)
+
+	return action != robots.PlanNotify
+}
+
+// restore поднимает позицию, сохраненную до рестарта или остановки воркера
+func (w *worker) restore() error {
+	st, err := w.s.repoState.GetState(w.robotID)
+	if err != nil {
+		return err
+	}
+
+	if st == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.trader.Restore(st)
+
+	w.s.logger.Debugf("state of robotID:%v restored side:%s entry:%v", w.robotID, w.trader.Side, w.trader.EntryPrice)
+
+	return nil
+}
+
+// saveState сохраняет состояние при остановке воркера, чтобы запомнить последнюю котировку
+func (w *worker) saveState() {
+	err := w.s.repoState.SaveState(w.trader.State(w.robotID))
+	if err != nil {
+		w.s.logger.Errorf("failed to save state of robotID:%v %s", w.robotID, err)
+	}
+}
+
+// setDegraded помечает робота, у которого слишком долго нет котировок
+func (w *worker) setDegraded(degraded bool) {
+	err := w.s.repoRobot.SetDegraded(w.robotID, degraded)
+	if err != nil {
+		w.s.logger.Errorf("failed to set degraded robotID:%v %s", w.robotID, err)
+
+		return
+	}
+
+	w.mu.Lock()
+	w.robot.IsDegraded = degraded
+	w.mu.Unlock()
+
+	w.s.logger.Warnw("robot feed state changed", "robotID", w.robotID, "ticker", w.robot.Ticker, "degraded", degraded)
+}
@@ -0,0 +1,389 @@
+package engine
+
+import (
+	"authDB/internal/accounts"
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"authDB/internal/robots"
+	"authDB/internal/strategy"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+)
+
+// feedClient поток котировок, которые отправляет сам тест
+type feedClient struct {
+	fintech.TradingServiceClient
+
+	mu      sync.Mutex
+	streams []*feedStream
+}
+
+type feedStream struct {
+	grpc.ClientStream
+
+	ctx context.Context
+	ch  chan *fintech.PriceResponse
+}
+
+func (c *feedClient) Price(ctx context.Context, in *fintech.PriceRequest,
+	opts ...grpc.CallOption) (fintech.TradingService_PriceClient, error) {
+	s := &feedStream{ctx: ctx, ch: make(chan *fintech.PriceResponse)}
+
+	c.mu.Lock()
+	c.streams = append(c.streams, s)
+	c.mu.Unlock()
+
+	return s, nil
+}
+
+func (s *feedStream) Recv() (*fintech.PriceResponse, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case data := <-s.ch:
+		return data, nil
+	}
+}
+
+// send отдает котировку в последний открытый поток
+func (c *feedClient) send(t *testing.T, data *fintech.PriceResponse) {
+	t.Helper()
+
+	var s *feedStream
+
+	eventually(t, "price stream", func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if n := len(c.streams); n > 0 && c.streams[n-1].ctx.Err() == nil {
+			s = c.streams[n-1]
+		}
+
+		return s != nil
+	})
+
+	select {
+	case s.ch <- data:
+	case <-time.After(waitTimeout):
+		t.Fatal("price stream is not read")
+	}
+}
+
+// ticks котировки с растущим временем
+type ticks struct {
+	at time.Time
+}
+
+func (q *ticks) next(buy, sell float64) *fintech.PriceResponse {
+	q.at = q.at.Add(time.Second)
+	ts, _ := ptypes.TimestampProto(q.at) // nolint
+
+	return &fintech.PriceResponse{BuyPrice: buy, SellPrice: sell, Ts: ts}
+}
+
+// processed ждет, пока воркер начнет обрабатывать котировку с временем data.Ts;
+// котировки обрабатываются по очереди, так что все предыдущие к этому моменту обработаны полностью
+func processed(t *testing.T, s *Supervisor, id int, data *fintech.PriceResponse) {
+	t.Helper()
+
+	want, _ := ptypes.Timestamp(data.Ts) // nolint
+
+	eventually(t, "tick processing", func() bool {
+		s.mu.Lock()
+		w, ok := s.workers[id]
+		s.mu.Unlock()
+
+		if !ok {
+			return false
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return w.trader != nil && w.trader.LastTickTs.Equal(want)
+	})
+}
+
+func TestWorkerRestoresPositionAfterRestart(t *testing.T) {
+	const id = 1
+
+	rob := liveRobot(id, "10", "12")
+	rob.TrailingStop.Value, rob.TrailingStop.Percent = decimal.NewFromInt(5), true
+
+	db := newRepos(rob)
+	b := &fakeBroker{}
+	q := &ticks{at: time.Now()}
+
+	client := &feedClient{}
+	first := db.supervisor(client, b)
+	stop := running(first)
+
+	client.send(t, q.next(10, 9.9))
+	eventually(t, "buy deal", func() bool { return db.deals.count() == 1 })
+
+	// новая лучшая цена сохраняется сразу, не дожидаясь остановки воркера,
+	// поэтому позиция переживает и падение процесса, а не только штатную остановку
+	client.send(t, q.next(11.1, 11))
+	eventually(t, "saved high water", func() bool {
+		st, _ := db.states.GetState(id)
+
+		return st != nil && st.Side == strategy.Long.String() && st.EntryPrice.Equal(decimal.NewFromInt(10)) &&
+			st.HighWater.Equal(decimal.NewFromInt(11))
+	})
+
+	stop()
+
+	second := db.supervisor(client, b)
+	stop = running(second)
+
+	defer stop()
+
+	// цена снова у порога покупки: открытая позиция не дает купить второй раз,
+	// а трейлинг-стоп от 11 (10.45) еще не сработал
+	client.send(t, q.next(10, 10.9))
+
+	last := q.next(10.5, 10.6)
+	client.send(t, last)
+	processed(t, second, id, last)
+
+	st2, ok := second.Status(id)
+	if !ok || st2.Side != strategy.Long.String() || !st2.EntryPrice.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("status after restart = %+v, want long from 10", st2)
+	}
+
+	second.mu.Lock()
+	w := second.workers[id]
+	second.mu.Unlock()
+
+	w.mu.Lock()
+	highWater, position := w.trader.HighWater, w.trader.Position
+	w.mu.Unlock()
+
+	if !highWater.Equal(decimal.NewFromInt(11)) || position != 1 {
+		t.Fatalf("restored high water %v position %d, want 11 and 1", highWater, position)
+	}
+
+	orders := b.placed()
+	if len(orders) != 1 || orders[0].Side != deals.Buy {
+		t.Fatalf("orders after restart = %d, want only the first buy", len(orders))
+	}
+}
+
+func TestWorkerKeepsFillUntilDealIsSaved(t *testing.T) {
+	const id = 1
+
+	saveRetryDelay = time.Millisecond
+
+	db := newRepos(liveRobot(id, "10", "12"))
+	b := &fakeBroker{}
+	q := &ticks{at: time.Now()}
+
+	client := &feedClient{}
+	s := db.supervisor(client, b)
+	stop := running(s)
+
+	defer stop()
+
+	// первая попытка и все повторы записи падают: биржа уже исполнила заявку, а сделки в базе нет
+	db.deals.fail(saveRetries + 1)
+
+	buy := q.next(10, 9.9)
+	client.send(t, buy)
+	processed(t, s, id, buy)
+
+	eventually(t, "pending order", func() bool {
+		st, _ := db.states.GetState(id)
+
+		return st != nil && st.Pending != nil && st.Pending.ClientOrderID == "1-0" && st.OrderSeq == 0
+	})
+
+	if st, _ := s.Status(id); st.Side != strategy.Flat.String() {
+		t.Fatalf("side = %s before the deal is saved, want flat", st.Side)
+	}
+
+	// следующая котировка повторяет ту же заявку, биржа возвращает уже случившееся исполнение
+	client.send(t, q.next(10.5, 10.4))
+	eventually(t, "saved deal", func() bool { return db.deals.count() == 1 })
+
+	last := q.next(10.5, 10.4)
+	client.send(t, last)
+	processed(t, s, id, last)
+
+	if n := len(b.placed()); n != 1 {
+		t.Fatalf("exchange executed %d orders, want 1", n)
+	}
+
+	st, _ := db.states.GetState(id)
+	if st.Pending != nil || st.OrderSeq != 1 || st.Side != strategy.Long.String() || !st.EntryPrice.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("state = %+v, want long from 10 with the next order 1", st)
+	}
+}
+
+func TestWorkerDeactivatesOnPlanYield(t *testing.T) {
+	const id = 1
+
+	rob := liveRobot(id, "10", "12")
+	rob.PlanYield = decimal.NewFromInt(1)
+	rob.OnPlanYield = robots.PlanDeactivate
+
+	db := newRepos(rob)
+	q := &ticks{at: time.Now()}
+
+	client := &feedClient{}
+	stop := running(db.supervisor(client, &fakeBroker{}))
+
+	defer stop()
+
+	client.send(t, q.next(10, 9.9))
+	client.send(t, q.next(12.1, 12))
+
+	// тот же итог в базе, что и у riskStop: робот снят с активации, а не просто переведен в draft
+	eventually(t, "deactivated robot", func() bool {
+		got, _ := db.robots.GetRobot(id)
+
+		return got.Status == robots.StatusDraft && !got.IsActive && got.DeactivatedAt.Valid &&
+			got.PlanOutcome == robots.PlanDeactivate.Outcome()
+	})
+}
+
+func TestWorkerAppliesUpdateKeepingPosition(t *testing.T) {
+	const id = 1
+
+	db := newRepos(liveRobot(id, "10", "12"))
+	q := &ticks{at: time.Now()}
+
+	client := &feedClient{}
+	s := db.supervisor(client, &fakeBroker{})
+	stop := running(s)
+
+	defer stop()
+
+	client.send(t, q.next(10, 9.9))
+	eventually(t, "buy deal", func() bool { return db.deals.count() == 1 })
+
+	// стоп-лосс меняется у запущенного робота, как через UpdateRobot
+	db.robots.change(id, func(rob *robots.Robot) { rob.StopLoss.Value = decimal.RequireFromString("0.5") }) // nolint
+	s.Reload(id)
+
+	eventually(t, "applied update", func() bool {
+		s.mu.Lock()
+		w, ok := s.workers[id]
+		s.mu.Unlock()
+
+		if !ok {
+			return false
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return w.robot.StopLoss.Value.Equal(decimal.RequireFromString("0.5")) && w.trader.Position == 1
+	})
+
+	client.send(t, q.next(10, 9.4))
+	eventually(t, "stop loss deal", func() bool { return db.deals.count() == 2 })
+
+	ds, _ := db.deals.GetRobotDeals(id)
+	if ds[1].Side != deals.Sell || ds[1].ExitReason != deals.ExitStopLoss {
+		t.Fatalf("second deal %s by %s, want sell by stop loss", ds[1].Side, ds[1].ExitReason)
+	}
+}
+
+func TestWorkerFailsOnUnknownStrategy(t *testing.T) {
+	const id = 1
+
+	rob := liveRobot(id, "10", "12")
+	rob.Status = robots.StatusScheduled
+	rob.Strategy = "unknown"
+
+	db := newRepos(rob)
+	s := db.supervisor(&feedClient{}, &fakeBroker{})
+	stop := running(s)
+
+	defer stop()
+
+	// воркер падает до перехода в running, поэтому failed должен быть доступен из scheduled
+	eventually(t, "failed robot", func() bool { return db.robots.status(id) == robots.StatusFailed })
+}
+
+func TestSupervisorDoesNotRestartFailedStart(t *testing.T) {
+	const id = 1
+
+	rob := liveRobot(id, "10", "12")
+	rob.Status = robots.StatusPaused
+
+	db := newRepos(rob)
+	db.states.getErr = errors.New("database is down")
+	db.robots.statusErr = errors.New("database is down")
+
+	s := db.supervisor(&feedClient{}, &fakeBroker{})
+	stop := running(s)
+
+	defer stop()
+
+	eventually(t, "failed start", func() bool { return db.states.reads() == 1 })
+
+	// статус failed не записался, робот в базе все еще paused, но несколько sync его не перезапускают
+	time.Sleep(20 * s.cfg.SyncInterval)
+
+	if n := db.states.reads(); n != 1 {
+		t.Fatalf("worker started %d times, want once", n)
+	}
+
+	// Reload после правки робота пользователем запускает его снова
+	db.states.mu.Lock()
+	db.states.getErr = nil
+	db.states.mu.Unlock()
+
+	db.robots.mu.Lock()
+	db.robots.statusErr = nil
+	db.robots.mu.Unlock()
+
+	s.Reload(id)
+
+	eventually(t, "restarted worker", func() bool {
+		_, ok := s.Status(id)
+
+		return ok && db.states.reads() == 2
+	})
+}
+
+func TestWorkerDoesNotRetryDealRejectedForFunds(t *testing.T) {
+	const id = 1
+
+	saveRetryDelay = time.Millisecond
+
+	db := newRepos(liveRobot(id, "10", "12"))
+	db.deals.failErr = errors.Wrap(accounts.ErrInsufficientFunds, "need 10.00, cash 0.00")
+	db.deals.fail(saveRetries + 1)
+
+	q := &ticks{at: time.Now()}
+	client := &feedClient{}
+	s := db.supervisor(client, &fakeBroker{})
+	stop := running(s)
+
+	defer stop()
+
+	buy := q.next(10, 9.9)
+	client.send(t, buy)
+	processed(t, s, id, buy)
+
+	// база отклонила вход по деньгам: повторы в той же котировке бессмысленны, заявка ждет следующей
+	eventually(t, "rejected deal", func() bool { return db.deals.left() < saveRetries+1 })
+	time.Sleep(50 * saveRetryDelay)
+
+	if st, _ := db.states.GetState(id); st == nil || st.Pending == nil {
+		t.Fatal("rejected order was not kept")
+	}
+
+	if n := db.deals.left(); n != saveRetries {
+		t.Fatalf("deal was saved %d times on one tick, want once", saveRetries+1-n)
+	}
+}
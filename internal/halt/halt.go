@@ -0,0 +1,20 @@
+package halt
+
+import (
+	"database/sql"
+)
+
+// State глобальная остановка торговли: роботы не открывают новые позиции,
+// а с Flatten еще и закрывают открытые
+type State struct {
+	Halted    bool
+	Flatten   bool
+	Reason    string
+	ChangedAt sql.NullTime
+}
+
+// Storage хранит остановку, чтобы она пережила рестарт
+type Storage interface {
+	Get() (*State, error)
+	Set(st *State) error
+}
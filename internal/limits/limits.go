@@ -0,0 +1,76 @@
+package limits
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ErrExceeded действие нарушает риск-лимит пользователя
+var ErrExceeded = errors.New("user limit exceeded")
+
+// Limits риск-лимиты пользователя, ноль выключает лимит
+type Limits struct {
+	UserID int
+	// MaxActiveRobots сколько роботов могут быть активированы одновременно
+	MaxActiveRobots int
+	// MaxTickerExposure и MaxTotalExposure стоимость открытых позиций по цене входа
+	MaxTickerExposure decimal.Decimal
+	MaxTotalExposure  decimal.Decimal
+	// MaxDealsPerDay сколько сделок роботы пользователя могут сделать за сутки
+	MaxDealsPerDay int
+	UpdatedAt      time.Time
+}
+
+// Usage текущая загрузка лимитов пользователя
+type Usage struct {
+	ActiveRobots   int
+	TickerExposure map[string]decimal.Decimal
+	TotalExposure  decimal.Decimal
+	DealsToday     int
+}
+
+// Storage хранилище лимитов
+type Storage interface {
+	// GetLimits возвращает пустые лимиты, если они не задавались
+	GetLimits(userID int) (*Limits, error)
+	SetLimits(l *Limits) error
+	GetUsage(userID int) (*Usage, error)
+}
+
+// Validate ...
+func (l *Limits) Validate() error {
+	if l.MaxActiveRobots < 0 || l.MaxDealsPerDay < 0 || l.MaxTickerExposure.IsNegative() || l.MaxTotalExposure.IsNegative() {
+		return errors.New("limits should not be negative")
+	}
+
+	return nil
+}
+
+// CheckActivate можно ли активировать еще одного робота
+func (l *Limits) CheckActivate(u *Usage) error {
+	if l.MaxActiveRobots > 0 && u.ActiveRobots >= l.MaxActiveRobots {
+		return errors.Wrap(ErrExceeded, "max active robots "+strconv.Itoa(l.MaxActiveRobots))
+	}
+
+	return nil
+}
+
+// CheckEntry можно ли открыть позицию стоимостью amount по тикеру
+func (l *Limits) CheckEntry(u *Usage, ticker string, amount decimal.Decimal) error {
+	if l.MaxDealsPerDay > 0 && u.DealsToday >= l.MaxDealsPerDay {
+		return errors.Wrap(ErrExceeded, "max deals per day "+strconv.Itoa(l.MaxDealsPerDay))
+	}
+
+	if l.MaxTickerExposure.IsPositive() && u.TickerExposure[ticker].Add(amount).GreaterThan(l.MaxTickerExposure) {
+		return errors.Wrap(ErrExceeded, "max exposure for "+ticker+" "+l.MaxTickerExposure.StringFixed(2))
+	}
+
+	if l.MaxTotalExposure.IsPositive() && u.TotalExposure.Add(amount).GreaterThan(l.MaxTotalExposure) {
+		return errors.Wrap(ErrExceeded, "max total exposure "+l.MaxTotalExposure.StringFixed(2))
+	}
+
+	return nil
+}
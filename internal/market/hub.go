@@ -0,0 +1,327 @@
+package market
+
+import (
+	"authDB/internal/clock"
+	"authDB/internal/fintech"
+	"authDB/pkg/logger"
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const subscriberBuffer = 16
+
+// Config настройки переподключения к потоку котировок
+type Config struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// FeedStats состояние потока котировок по тикеру
+type FeedStats struct {
+	Subscribers int
+	Reconnects  int
+	Connected   bool
+	DownSince   time.Time `json:",omitempty"`
+}
+
+// Tap получает каждую котировку, пришедшую от биржи; не должен блокировать
+type Tap interface {
+	Record(ticker string, data *fintech.PriceResponse)
+}
+
+// Hub держит один поток котировок на тикер и раздает его всем подписчикам
+type Hub struct {
+	client fintech.TradingServiceClient
+	logger logger.Logger
+	cfg    Config
+	tap    Tap
+
+	mu    sync.Mutex
+	feeds map[string]*feed
+}
+
+type feed struct {
+	ticker string
+	cancel context.CancelFunc
+	subs   map[*Subscription]struct{}
+
+	connected  bool
+	reconnects int
+	downSince  time.Time
+}
+
+// Subscription подписка на котировки одного тикера
+type Subscription struct {
+	C <-chan *fintech.PriceResponse
+
+	ch     chan *fintech.PriceResponse
+	hub    *Hub
+	ticker string
+	once   sync.Once
+}
+
+// NewHub ...
+func NewHub(client fintech.TradingServiceClient, logger logger.Logger, cfg Config) *Hub {
+	return &Hub{
+		client: client,
+		logger: logger,
+		cfg:    cfg,
+		feeds:  make(map[string]*feed),
+	}
+}
+
+// SetTap подключает получателя всех котировок, например запись в базу
+func (h *Hub) SetTap(tap Tap) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tap = tap
+}
+
+// Subscribe подписывает на тикер, открывая поток, если подписчиков еще не было
+func (h *Hub) Subscribe(ticker string) *Subscription {
+	ch := make(chan *fintech.PriceResponse, subscriberBuffer)
+	sub := &Subscription{
+		C:      ch,
+		ch:     ch,
+		hub:    h,
+		ticker: ticker,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, ok := h.feeds[ticker]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		f = &feed{
+			ticker:    ticker,
+			cancel:    cancel,
+			subs:      make(map[*Subscription]struct{}),
+			downSince: clock.Now(),
+		}
+		h.feeds[ticker] = f
+
+		go h.run(ctx, f)
+	}
+
+	f.subs[sub] = struct{}{}
+
+	return sub
+}
+
+// Close отписывает от тикера, поток закрывается вместе с последним подписчиком
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.unsubscribe(s)
+	})
+}
+
+func (h *Hub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, ok := h.feeds[sub.ticker]
+	if !ok {
+		return
+	}
+
+	if _, ok := f.subs[sub]; !ok {
+		return
+	}
+
+	delete(f.subs, sub)
+	close(sub.ch)
+
+	if len(f.subs) == 0 {
+		f.cancel()
+		delete(h.feeds, sub.ticker)
+		h.logger.Debugf("price stream with ticker:%s has no subscribers", sub.ticker)
+	}
+}
+
+// Subscribers количество подписчиков по тикерам
+func (h *Hub) Subscribers() map[string]int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts := make(map[string]int, len(h.feeds))
+	for ticker, f := range h.feeds {
+		counts[ticker] = len(f.subs)
+	}
+
+	return counts
+}
+
+// Stats состояние потоков по тикерам
+func (h *Hub) Stats() map[string]FeedStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := make(map[string]FeedStats, len(h.feeds))
+	for ticker, f := range h.feeds {
+		st := FeedStats{
+			Subscribers: len(f.subs),
+			Reconnects:  f.reconnects,
+			Connected:   f.connected,
+		}
+
+		if !f.connected {
+			st.DownSince = f.downSince
+		}
+
+		stats[ticker] = st
+	}
+
+	return stats
+}
+
+// DownFor сколько времени поток по тикеру не присылает котировок
+func (h *Hub) DownFor(ticker string) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, ok := h.feeds[ticker]
+	if !ok || f.connected {
+		return 0
+	}
+
+	return clock.Now().Sub(f.downSince)
+}
+
+// run держит поток открытым, переподключаясь с экспоненциальной задержкой
+func (h *Hub) run(ctx context.Context, f *feed) {
+	defer h.closeFeed(f)
+
+	backoff := h.cfg.MinBackoff
+
+	for {
+		received, err := h.consume(ctx, f)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if received {
+			backoff = h.cfg.MinBackoff
+		}
+
+		reconnects := h.markDown(f)
+		delay := jitter(backoff)
+
+		h.logger.Warnw("price stream is down, reconnecting",
+			"ticker", f.ticker, "reconnects", reconnects, "delay", delay.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		backoff *= 2
+		if backoff > h.cfg.MaxBackoff {
+			backoff = h.cfg.MaxBackoff
+		}
+	}
+}
+
+// consume читает поток до первой ошибки, received показывает, была ли хоть одна котировка
+func (h *Hub) consume(ctx context.Context, f *feed) (bool, error) {
+	res, err := h.client.Price(ctx, &fintech.PriceRequest{Ticker: f.ticker})
+	if err != nil {
+		return false, errors.Wrap(err, "stream failed")
+	}
+
+	h.logger.Debugf("price stream is starting with ticker:%s", f.ticker)
+
+	received := false
+
+	for {
+		data, err := res.Recv()
+		if err != nil {
+			return received, errors.Wrap(err, "can't receive from server")
+		}
+
+		if !received {
+			received = true
+
+			h.markUp(f)
+		}
+
+		h.broadcast(f, data)
+	}
+}
+
+func (h *Hub) markUp(f *feed) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f.connected = true
+}
+
+func (h *Hub) markDown(f *feed) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if f.connected {
+		f.connected = false
+		f.downSince = clock.Now()
+	}
+
+	f.reconnects++
+
+	return f.reconnects
+}
+
+// jitter возвращает случайную задержку в диапазоне [d/2, d)
+func jitter(d time.Duration) time.Duration {
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+
+	return time.Duration(half + rand.Int63n(half)) // nolint
+}
+
+// broadcast раздает котировку подписчикам; tap вызывается уже без блокировки,
+// чтобы медленная запись не держала Subscribe, Stats и DownFor по всем тикерам
+func (h *Hub) broadcast(f *feed, data *fintech.PriceResponse) {
+	h.mu.Lock()
+
+	tap := h.tap
+
+	for sub := range f.subs {
+		select {
+		case sub.ch <- data:
+		default:
+			h.logger.Debugf("subscriber with ticker:%s is too slow, tick dropped", f.ticker)
+		}
+	}
+
+	h.mu.Unlock()
+
+	if tap != nil {
+		tap.Record(f.ticker, data)
+	}
+}
+
+// closeFeed закрывает подписчиков после остановки потока
+func (h *Hub) closeFeed(f *feed) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.feeds[f.ticker] != f {
+		return
+	}
+
+	for sub := range f.subs {
+		close(sub.ch)
+		delete(f.subs, sub)
+	}
+
+	delete(h.feeds, f.ticker)
+	f.cancel()
+}
@@ -0,0 +1,220 @@
+package market
+
+import (
+	"authDB/internal/fintech"
+	"authDB/pkg/logger"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const waitTimeout = time.Second
+
+// fakeClient отдает по потоку на каждый вызов Price, котировки в поток пишет тест
+type fakeClient struct {
+	fintech.TradingServiceClient
+
+	mu      sync.Mutex
+	streams map[string][]*fakeStream
+}
+
+type fakeStream struct {
+	grpc.ClientStream
+
+	ctx context.Context
+	ch  chan *fintech.PriceResponse
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{streams: make(map[string][]*fakeStream)}
+}
+
+func (c *fakeClient) Price(ctx context.Context, in *fintech.PriceRequest,
+	opts ...grpc.CallOption) (fintech.TradingService_PriceClient, error) {
+	s := &fakeStream{ctx: ctx, ch: make(chan *fintech.PriceResponse)}
+
+	c.mu.Lock()
+	c.streams[in.Ticker] = append(c.streams[in.Ticker], s)
+	c.mu.Unlock()
+
+	return s, nil
+}
+
+func (s *fakeStream) Recv() (*fintech.PriceResponse, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	case data := <-s.ch:
+		return data, nil
+	}
+}
+
+func (c *fakeClient) opened(ticker string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.streams[ticker])
+}
+
+// stream ждет первый открытый поток по тикеру
+func (c *fakeClient) stream(t *testing.T, ticker string) *fakeStream {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		ss := c.streams[ticker]
+		c.mu.Unlock()
+
+		if len(ss) > 0 {
+			return ss[0]
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("price stream for %s was not opened", ticker)
+
+	return nil
+}
+
+func (s *fakeStream) send(t *testing.T, data *fintech.PriceResponse) {
+	t.Helper()
+
+	select {
+	case s.ch <- data:
+	case <-time.After(waitTimeout):
+		t.Fatal("hub does not read the price stream")
+	}
+}
+
+func receive(t *testing.T, sub *Subscription) *fintech.PriceResponse {
+	t.Helper()
+
+	select {
+	case data, ok := <-sub.C:
+		if !ok {
+			t.Fatal("subscription was closed")
+		}
+
+		return data
+	case <-time.After(waitTimeout):
+		t.Fatal("tick was not delivered")
+	}
+
+	return nil
+}
+
+func newTestHub(client fintech.TradingServiceClient) *Hub {
+	return NewHub(client, logger.NewNop(), Config{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+}
+
+func TestHubSharesOneStreamPerTicker(t *testing.T) {
+	client := newFakeClient()
+	hub := newTestHub(client)
+
+	first, second := hub.Subscribe("SBER"), hub.Subscribe("SBER")
+	other := hub.Subscribe("GAZP")
+
+	defer other.Close()
+
+	got := hub.Subscribers()
+	if got["SBER"] != 2 || got["GAZP"] != 1 || len(got) != 2 {
+		t.Fatalf("subscribers = %v, want SBER:2 GAZP:1", got)
+	}
+
+	stream := client.stream(t, "SBER")
+
+	for _, price := range []float64{10, 10.5, 11} {
+		stream.send(t, &fintech.PriceResponse{BuyPrice: price})
+
+		for _, sub := range []*Subscription{first, second} {
+			if data := receive(t, sub); data.BuyPrice != price {
+				t.Fatalf("got price %v, want %v", data.BuyPrice, price)
+			}
+		}
+	}
+
+	if n := client.opened("SBER"); n != 1 {
+		t.Fatalf("opened %d SBER streams, want 1", n)
+	}
+
+	first.Close()
+
+	if got := hub.Subscribers()["SBER"]; got != 1 {
+		t.Fatalf("SBER subscribers after close = %d, want 1", got)
+	}
+
+	select {
+	case <-stream.ctx.Done():
+		t.Fatal("stream was cancelled while it still has a subscriber")
+	default:
+	}
+
+	second.Close()
+
+	select {
+	case <-stream.ctx.Done():
+	case <-time.After(waitTimeout):
+		t.Fatal("stream was not cancelled after the last subscriber left")
+	}
+
+	if _, ok := hub.Subscribers()["SBER"]; ok {
+		t.Fatal("SBER feed is still registered")
+	}
+
+	if _, ok := <-second.C; ok {
+		t.Fatal("subscription channel was not closed")
+	}
+}
+
+// blockingTap держит Record, пока тест его не отпустит
+type blockingTap struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingTap) Record(ticker string, data *fintech.PriceResponse) {
+	b.entered <- struct{}{}
+	<-b.release
+}
+
+func TestHubSlowTapDoesNotBlockHub(t *testing.T) {
+	client := newFakeClient()
+	hub := newTestHub(client)
+	tap := &blockingTap{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	hub.SetTap(tap)
+
+	sub := hub.Subscribe("SBER")
+	defer sub.Close()
+
+	client.stream(t, "SBER").send(t, &fintech.PriceResponse{BuyPrice: 10})
+
+	select {
+	case <-tap.entered:
+	case <-time.After(waitTimeout):
+		t.Fatal("tap did not receive the tick")
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		other := hub.Subscribe("GAZP")
+		other.Close()
+		hub.Stats()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(waitTimeout):
+		t.Fatal("hub is blocked by a slow tap")
+	}
+
+	close(tap.release)
+
+	receive(t, sub)
+}
@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"authDB/internal/accounts"
+	"authDB/internal/deals"
+	"database/sql"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var _ accounts.Storage = &AccountStorage{}
+
+// AccountStorage ...
+type AccountStorage struct {
+	statementStorage
+
+	getStmt             *sql.Stmt
+	getHoldingsStmt     *sql.Stmt
+	getTransactionsStmt *sql.Stmt
+	addCashStmt         *sql.Stmt
+	journalStmt         *sql.Stmt
+}
+
+// NewAccountStorage ...
+func NewAccountStorage(db *DB) (*AccountStorage, error) {
+	s := &AccountStorage{statementStorage: newStatementsStorage(db)}
+
+	stmts := []stmt{
+		{Query: getAccountQuery, Dst: &s.getStmt},
+		{Query: getHoldingsQuery, Dst: &s.getHoldingsStmt},
+		{Query: getTransactionsQuery, Dst: &s.getTransactionsStmt},
+		{Query: addCashQuery, Dst: &s.addCashStmt},
+		{Query: journalQuery, Dst: &s.journalStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const getAccountQuery = "SELECT user_id, cash, updated_at FROM public.accounts WHERE user_id=$1"
+
+// GetAccount ...
+func (s *AccountStorage) GetAccount(userID int) (*accounts.Account, error) {
+	a := accounts.Account{UserID: userID}
+
+	err := s.getStmt.QueryRow(userID).Scan(&a.UserID, &a.Cash, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &a, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "can not scan account of user "+strconv.Itoa(userID))
+	}
+
+	return &a, nil
+}
+
+const holdingFields = "ticker, quantity, avg_price, realized_pnl, updated_at"
+
+const getHoldingsQuery = "SELECT " + holdingFields + " FROM public.holdings WHERE user_id=$1 ORDER BY ticker"
+
+// GetHoldings ...
+func (s *AccountStorage) GetHoldings(userID int) ([]*accounts.Holding, error) {
+	var hs []*accounts.Holding
+
+	idStr := strconv.Itoa(userID)
+
+	rows, err := s.getHoldingsStmt.Query(userID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get holdings of user "+idStr)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var h accounts.Holding
+
+		if err := scanHolding(rows, &h); err != nil {
+			return nil, errors.WithMessage(err, "failed to scan holdings of user "+idStr)
+		}
+
+		hs = append(hs, &h)
+	}
+
+	return hs, rows.Err()
+}
+
+const transactionFields = "user_id, kind, ticker, quantity, price, amount, deal_id, robot_id, note, created_at"
+
+const getTransactionsQuery = "SELECT id, " + transactionFields + " FROM public.transactions WHERE user_id=$1 ORDER BY id"
+
+// GetTransactions ...
+func (s *AccountStorage) GetTransactions(userID int) ([]*accounts.Transaction, error) {
+	var ts []*accounts.Transaction
+
+	idStr := strconv.Itoa(userID)
+
+	rows, err := s.getTransactionsStmt.Query(userID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get transactions of user "+idStr)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var t accounts.Transaction
+
+		err := rows.Scan(&t.ID, &t.UserID, &t.Kind, &t.Ticker, &t.Quantity, &t.Price, &t.Amount, &t.DealID, &t.RobotID,
+			&t.Note, &t.CreatedAt)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to scan transactions of user "+idStr)
+		}
+
+		ts = append(ts, &t)
+	}
+
+	return ts, rows.Err()
+}
+
+const addCashQuery = "INSERT INTO public.accounts (user_id, cash, updated_at) VALUES ($1, $2, now()) " +
+	"ON CONFLICT (user_id) DO UPDATE SET cash=accounts.cash + EXCLUDED.cash, updated_at=now() RETURNING cash"
+
+const journalQuery = "INSERT INTO public.transactions (" + transactionFields + ") " +
+	"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now()) RETURNING id, created_at"
+
+// Transfer ...
+func (s *AccountStorage) Transfer(userID int, amount decimal.Decimal, note string) (*accounts.Transaction, error) {
+	idStr := strconv.Itoa(userID)
+
+	t := &accounts.Transaction{UserID: userID, Kind: accounts.KindDeposit, Amount: amount, Note: note}
+	if amount.IsNegative() {
+		t.Kind = accounts.KindWithdraw
+	}
+
+	tx, err := s.db.Session.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't begin tx for account "+idStr)
+	}
+
+	var cash decimal.Decimal
+
+	if err = tx.Stmt(s.addCashStmt).QueryRow(userID, amount).Scan(&cash); err != nil {
+		tx.Rollback() // nolint
+
+		return nil, errors.WithMessage(err, "failed to update cash of user "+idStr)
+	}
+
+	if cash.IsNegative() {
+		tx.Rollback() // nolint
+
+		return nil, errors.Wrap(accounts.ErrInsufficientFunds, "can't withdraw "+amount.Neg().StringFixed(2))
+	}
+
+	if err = journal(tx.Stmt(s.journalStmt), t); err != nil {
+		tx.Rollback() // nolint
+
+		return nil, errors.WithMessage(err, "failed to journal transfer of user "+idStr)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "can't commit transfer of user "+idStr)
+	}
+
+	return t, nil
+}
+
+const ensureHoldingQuery = "INSERT INTO public.holdings (user_id, " + holdingFields + ") VALUES ($1, $2, 0, 0, 0, now()) " +
+	"ON CONFLICT (user_id, ticker) DO NOTHING"
+
+const lockHoldingQuery = "SELECT " + holdingFields + " FROM public.holdings WHERE user_id=$1 AND ticker=$2 FOR UPDATE"
+
+const saveHoldingQuery = "UPDATE public.holdings SET quantity=$3, avg_price=$4, realized_pnl=$5, updated_at=now() " +
+	"WHERE user_id=$1 AND ticker=$2"
+
+// postDeal проводит сделку по счету владельца робота внутри транзакции сделки:
+// двигает деньги, пересчитывает позицию по тикеру и пишет журнал.
+// Вход в позицию проверяется по деньгам так же, как перед заявкой: покупка не уводит счет в минус,
+// short требует денег на сумму входа в качестве обеспечения. Выходы не ограничиваются
+func (s *DealStorage) postDeal(tx *sql.Tx, userID int, ticker string, d *deals.Deal) error {
+	var h accounts.Holding
+
+	if _, err := tx.Stmt(s.ensureHoldingStmt).Exec(userID, ticker); err != nil {
+		return errors.WithMessage(err, "failed to create holding")
+	}
+
+	if err := scanHolding(tx.Stmt(s.lockHoldingStmt).QueryRow(userID, ticker), &h); err != nil {
+		return errors.WithMessage(err, "failed to lock holding")
+	}
+
+	opens := h.Opens(d.Side)
+
+	h.Apply(d.Side, d.Quantity, d.Price)
+
+	if _, err := tx.Stmt(s.saveHoldingStmt).Exec(userID, ticker, h.Quantity, h.AvgPrice, h.RealizedPnL); err != nil {
+		return errors.WithMessage(err, "failed to save holding")
+	}
+
+	t := accounts.ForDeal(userID, ticker, d)
+
+	var cash decimal.Decimal
+
+	if err := tx.Stmt(s.addCashStmt).QueryRow(userID, t.Amount).Scan(&cash); err != nil {
+		return errors.WithMessage(err, "failed to update cash")
+	}
+
+	if opens {
+		before := accounts.Account{UserID: userID, Cash: cash.Sub(t.Amount)}
+		if err := before.CheckFunds(t.Amount.Abs()); err != nil {
+			return err
+		}
+	}
+
+	return journal(tx.Stmt(s.journalStmt), t)
+}
+
+func journal(st *sql.Stmt, t *accounts.Transaction) error {
+	return st.QueryRow(t.UserID, t.Kind, t.Ticker, t.Quantity, t.Price, t.Amount, t.DealID, t.RobotID, t.Note).
+		Scan(&t.ID, &t.CreatedAt)
+}
+
+func scanHolding(scanner sqlScanner, h *accounts.Holding) error {
+	return scanner.Scan(&h.Ticker, &h.Quantity, &h.AvgPrice, &h.RealizedPnL, &h.UpdatedAt)
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"authDB/internal/deals"
+	"authDB/internal/robots"
+	"database/sql"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var _ deals.Deals = &DealStorage{}
+
+// DealStorage ...
+type DealStorage struct {
+	statementStorage
+
+	createStmt        *sql.Stmt
+	updateActualStmt  *sql.Stmt
+	saveStateStmt     *sql.Stmt
+	getRobotDealsStmt *sql.Stmt
+
+	ensureHoldingStmt *sql.Stmt
+	lockHoldingStmt   *sql.Stmt
+	saveHoldingStmt   *sql.Stmt
+	addCashStmt       *sql.Stmt
+	journalStmt       *sql.Stmt
+}
+
+// NewDealStorage ...
+func NewDealStorage(db *DB) (*DealStorage, error) {
+	s := &DealStorage{statementStorage: newStatementsStorage(db)}
+
+	stmts := []stmt{
+		{Query: createDealQuery, Dst: &s.createStmt},
+		{Query: updateActualRobotStmtQuery, Dst: &s.updateActualStmt},
+		{Query: saveStateQuery, Dst: &s.saveStateStmt},
+		{Query: getRobotDealsQuery, Dst: &s.getRobotDealsStmt},
+		{Query: ensureHoldingQuery, Dst: &s.ensureHoldingStmt},
+		{Query: lockHoldingQuery, Dst: &s.lockHoldingStmt},
+		{Query: saveHoldingQuery, Dst: &s.saveHoldingStmt},
+		{Query: addCashQuery, Dst: &s.addCashStmt},
+		{Query: journalQuery, Dst: &s.journalStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const dealFields = "robot_id, side, price, quantity, quote_ts, executed_at, order_id, exit_reason, position"
+
+const createDealQuery = "INSERT INTO public.deals (" + dealFields + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id"
+
+// Save ...
+func (s *DealStorage) Save(d *deals.Deal, rob *robots.Robot, st *robots.State) error {
+	idStr := strconv.Itoa(rob.RobotID)
+
+	tx, err := s.db.Session.Begin()
+	if err != nil {
+		return errors.Wrap(err, "can't begin tx for robot "+idStr)
+	}
+
+	err = tx.Stmt(s.createStmt).QueryRow(d.RobotID, d.Side, d.Price, d.Quantity, d.QuoteTs, d.ExecutedAt, d.OrderID, d.ExitReason, d.Position).Scan(&d.DealID)
+	if err != nil {
+		tx.Rollback() // nolint
+
+		return errors.WithMessage(err, "failed to create deal for robot "+idStr)
+	}
+
+	if err = s.postDeal(tx, rob.OwnerUserID, rob.Ticker, d); err != nil {
+		tx.Rollback() // nolint
+
+		return errors.WithMessage(err, "failed to post deal of robot "+idStr)
+	}
+
+	_, err = tx.Stmt(s.updateActualStmt).Exec(rob.FactYield, rob.DealsCount, rob.RobotID)
+	if err != nil {
+		tx.Rollback() // nolint
+
+		return errors.WithMessage(err, "failed to update robot with id"+idStr)
+	}
+
+	args, err := stateArgs(st)
+	if err == nil {
+		_, err = tx.Stmt(s.saveStateStmt).Exec(args...)
+	}
+
+	if err != nil {
+		tx.Rollback() // nolint
+
+		return errors.WithMessage(err, "failed to save state of robot "+idStr)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "can't commit deal for robot "+idStr)
+	}
+
+	return nil
+}
+
+const getRobotDealsQuery = "SELECT id, " + dealFields + " FROM public.deals WHERE robot_id=$1 ORDER BY executed_at, id"
+
+// GetRobotDeals ...
+func (s *DealStorage) GetRobotDeals(robotID int) ([]*deals.Deal, error) {
+	var dls []*deals.Deal
+
+	idStr := strconv.Itoa(robotID)
+
+	rows, err := s.getRobotDealsStmt.Query(robotID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get deals with robot_id "+idStr)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var d deals.Deal
+
+		if err := scanDeal(rows, &d); err != nil {
+			return nil, errors.WithMessage(err, "failed to scan deals with robot_id "+idStr)
+		}
+
+		dls = append(dls, &d)
+	}
+
+	return dls, rows.Err()
+}
+
+func scanDeal(scanner sqlScanner, d *deals.Deal) error {
+	return scanner.Scan(&d.DealID, &d.RobotID, &d.Side, &d.Price, &d.Quantity, &d.QuoteTs, &d.ExecutedAt, &d.OrderID, &d.ExitReason, &d.Position)
+}
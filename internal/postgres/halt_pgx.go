@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"authDB/internal/halt"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+var _ halt.Storage = &HaltStorage{}
+
+// HaltStorage ...
+type HaltStorage struct {
+	statementStorage
+
+	getStmt *sql.Stmt
+	setStmt *sql.Stmt
+}
+
+// NewHaltStorage ...
+func NewHaltStorage(db *DB) (*HaltStorage, error) {
+	s := &HaltStorage{statementStorage: newStatementsStorage(db)}
+
+	stmts := []stmt{
+		{Query: getHaltQuery, Dst: &s.getStmt},
+		{Query: setHaltQuery, Dst: &s.setStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const getHaltQuery = "SELECT halted, flatten, reason, changed_at FROM public.trading_halt WHERE id=1"
+
+// Get ...
+func (s *HaltStorage) Get() (*halt.State, error) {
+	var st halt.State
+
+	err := s.getStmt.QueryRow().Scan(&st.Halted, &st.Flatten, &st.Reason, &st.ChangedAt)
+	if err == sql.ErrNoRows {
+		return &st, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "can not scan trading halt")
+	}
+
+	return &st, nil
+}
+
+const setHaltQuery = "INSERT INTO public.trading_halt (id, halted, flatten, reason, changed_at) VALUES (1, $1, $2, $3, now()) " +
+	"ON CONFLICT (id) DO UPDATE SET halted=EXCLUDED.halted, flatten=EXCLUDED.flatten, reason=EXCLUDED.reason, " +
+	"changed_at=now() RETURNING changed_at"
+
+// Set ...
+func (s *HaltStorage) Set(st *halt.State) error {
+	if err := s.setStmt.QueryRow(st.Halted, st.Flatten, st.Reason).Scan(&st.ChangedAt); err != nil {
+		return errors.WithMessage(err, "failed to set trading halt")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"authDB/internal/limits"
+	"authDB/internal/robots"
+	"database/sql"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var _ limits.Storage = &LimitStorage{}
+
+// LimitStorage ...
+type LimitStorage struct {
+	statementStorage
+
+	getStmt          *sql.Stmt
+	setStmt          *sql.Stmt
+	activeRobotsStmt *sql.Stmt
+	exposureStmt     *sql.Stmt
+	dealsTodayStmt   *sql.Stmt
+}
+
+// NewLimitStorage ...
+func NewLimitStorage(db *DB) (*LimitStorage, error) {
+	s := &LimitStorage{statementStorage: newStatementsStorage(db)}
+
+	stmts := []stmt{
+		{Query: getLimitsQuery, Dst: &s.getStmt},
+		{Query: setLimitsQuery, Dst: &s.setStmt},
+		{Query: activeRobotsQuery, Dst: &s.activeRobotsStmt},
+		{Query: exposureQuery, Dst: &s.exposureStmt},
+		{Query: dealsTodayQuery, Dst: &s.dealsTodayStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const limitFields = "user_id, max_active_robots, max_ticker_exposure, max_total_exposure, max_deals_per_day, updated_at"
+
+const getLimitsQuery = "SELECT " + limitFields + " FROM public.user_limits WHERE user_id=$1"
+
+// GetLimits ...
+func (s *LimitStorage) GetLimits(userID int) (*limits.Limits, error) {
+	l := limits.Limits{UserID: userID}
+
+	err := s.getStmt.QueryRow(userID).Scan(&l.UserID, &l.MaxActiveRobots, &l.MaxTickerExposure, &l.MaxTotalExposure,
+		&l.MaxDealsPerDay, &l.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return &l, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "can not scan limits of user "+strconv.Itoa(userID))
+	}
+
+	return &l, nil
+}
+
+const setLimitsQuery = "INSERT INTO public.user_limits (" + limitFields + ") VALUES ($1, $2, $3, $4, $5, now()) " +
+	"ON CONFLICT (user_id) DO UPDATE SET max_active_robots=EXCLUDED.max_active_robots, " +
+	"max_ticker_exposure=EXCLUDED.max_ticker_exposure, max_total_exposure=EXCLUDED.max_total_exposure, " +
+	"max_deals_per_day=EXCLUDED.max_deals_per_day, updated_at=now() RETURNING updated_at"
+
+// SetLimits ...
+func (s *LimitStorage) SetLimits(l *limits.Limits) error {
+	err := s.setStmt.QueryRow(l.UserID, l.MaxActiveRobots, l.MaxTickerExposure, l.MaxTotalExposure, l.MaxDealsPerDay).
+		Scan(&l.UpdatedAt)
+	if err != nil {
+		return errors.WithMessage(err, "failed to set limits of user "+strconv.Itoa(l.UserID))
+	}
+
+	return nil
+}
+
+const activeRobotsQuery = "SELECT count(*) FROM public.robots WHERE owner_user_id=$1 AND status = ANY($2)"
+
+const exposureQuery = "SELECT r.ticker, sum(st.entry_price * st.quantity) FROM public.robot_states st " +
+	"JOIN public.robots r ON r.id = st.robot_id WHERE r.owner_user_id=$1 AND st.side <> 'flat' GROUP BY r.ticker"
+
+const dealsTodayQuery = "SELECT count(*) FROM public.deals d JOIN public.robots r ON r.id = d.robot_id " +
+	"WHERE r.owner_user_id=$1 AND d.executed_at >= date_trunc('day', now())"
+
+// GetUsage ...
+func (s *LimitStorage) GetUsage(userID int) (*limits.Usage, error) {
+	u := limits.Usage{TickerExposure: make(map[string]decimal.Decimal)}
+
+	idStr := strconv.Itoa(userID)
+
+	live := []string{string(robots.StatusScheduled), string(robots.StatusRunning), string(robots.StatusPaused)}
+
+	if err := s.activeRobotsStmt.QueryRow(userID, pq.Array(live)).Scan(&u.ActiveRobots); err != nil {
+		return nil, errors.WithMessage(err, "failed to count active robots of user "+idStr)
+	}
+
+	if err := s.dealsTodayStmt.QueryRow(userID).Scan(&u.DealsToday); err != nil {
+		return nil, errors.WithMessage(err, "failed to count deals of user "+idStr)
+	}
+
+	rows, err := s.exposureStmt.Query(userID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get exposure of user "+idStr)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ticker string
+			amount decimal.Decimal
+		)
+
+		if err := rows.Scan(&ticker, &amount); err != nil {
+			return nil, errors.WithMessage(err, "failed to scan exposure of user "+idStr)
+		}
+
+		u.TickerExposure[ticker] = amount
+		u.TotalExposure = u.TotalExposure.Add(amount)
+	}
+
+	return &u, rows.Err()
+}
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"authDB/internal/quotes"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+var _ quotes.Quotes = &QuoteStorage{}
+
+// QuoteStorage хранит котировки в таблице quotes, разбитой на партиции по дням
+type QuoteStorage struct {
+	statementStorage
+
+	findStmt           *sql.Stmt
+	latestStmt         *sql.Stmt
+	listPartitionsStmt *sql.Stmt
+
+	mu         sync.Mutex
+	partitions map[string]bool
+}
+
+// NewQuoteStorage ...
+func NewQuoteStorage(db *DB) (*QuoteStorage, error) {
+	s := &QuoteStorage{
+		statementStorage: newStatementsStorage(db),
+		partitions:       make(map[string]bool),
+	}
+
+	stmts := []stmt{
+		{Query: findQuotesQuery, Dst: &s.findStmt},
+		{Query: latestQuoteQuery, Dst: &s.latestStmt},
+		{Query: listQuotePartitionsQuery, Dst: &s.listPartitionsStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const (
+	partitionPrefix = "quotes_"
+	partitionLayout = "20060102"
+	day             = 24 * time.Hour
+)
+
+// SaveBatch пишет пачку котировок одним COPY. Партиции создаются в той же транзакции,
+// чтобы неудачная пачка не оставляла после себя пустых партиций
+func (s *QuoteStorage) SaveBatch(qs []*quotes.Quote) error {
+	tx, err := s.db.Session.Begin()
+	if err != nil {
+		return errors.Wrap(err, "can't begin tx for quotes")
+	}
+
+	created := make(map[string]bool)
+
+	for _, q := range qs {
+		if err = s.ensurePartition(tx, q.Ts, created); err != nil {
+			tx.Rollback() // nolint
+
+			return err
+		}
+	}
+
+	copyStmt, err := tx.Prepare(pq.CopyIn("quotes", "ticker", "buy_price", "sell_price", "ts"))
+	if err != nil {
+		tx.Rollback() // nolint
+
+		return errors.Wrap(err, "can't prepare copy of quotes")
+	}
+
+	for _, q := range qs {
+		if _, err = copyStmt.Exec(q.Ticker, q.BuyPrice, q.SellPrice, q.Ts); err != nil {
+			tx.Rollback() // nolint
+
+			return errors.Wrap(err, "can't copy quote")
+		}
+	}
+
+	if _, err = copyStmt.Exec(); err != nil {
+		tx.Rollback() // nolint
+
+		return errors.Wrap(err, "can't flush copy of quotes")
+	}
+
+	if err = copyStmt.Close(); err != nil {
+		tx.Rollback() // nolint
+
+		return errors.Wrap(err, "can't close copy of quotes")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "can't commit quotes")
+	}
+
+	s.mu.Lock()
+	for name := range created {
+		s.partitions[name] = true
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ensurePartition создает в tx партицию на день котировки, если ее еще нет.
+// В кэш партиций имена из created попадают только после коммита
+func (s *QuoteStorage) ensurePartition(tx *sql.Tx, ts time.Time, created map[string]bool) error {
+	from := ts.UTC().Truncate(day)
+	name := partitionPrefix + from.Format(partitionLayout)
+
+	s.mu.Lock()
+	known := s.partitions[name]
+	s.mu.Unlock()
+
+	if known || created[name] {
+		return nil
+	}
+
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS public.%s PARTITION OF public.quotes FOR VALUES FROM ('%s') TO ('%s')",
+		name, from.Format(time.RFC3339), from.Add(day).Format(time.RFC3339))
+
+	if _, err := tx.Exec(query); err != nil {
+		return errors.Wrap(err, "can't create partition "+name)
+	}
+
+	created[name] = true
+
+	return nil
+}
+
+const findQuotesQuery = "SELECT ticker, buy_price, sell_price, ts FROM public.quotes " +
+	"WHERE ticker=$1 AND ts>=$2 AND ts<$3 ORDER BY ts"
+
+// Find ...
+func (s *QuoteStorage) Find(ticker string, from, to time.Time) ([]*quotes.Quote, error) {
+	var qs []*quotes.Quote
+
+	rows, err := s.findStmt.Query(ticker, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get quotes with ticker "+ticker)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var q quotes.Quote
+
+		if err := rows.Scan(&q.Ticker, &q.BuyPrice, &q.SellPrice, &q.Ts); err != nil {
+			return nil, errors.WithMessage(err, "failed to scan quotes with ticker "+ticker)
+		}
+
+		qs = append(qs, &q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.WithMessage(err, "failed to read quotes with ticker "+ticker)
+	}
+
+	return qs, nil
+}
+
+const latestQuoteQuery = "SELECT ticker, buy_price, sell_price, ts FROM public.quotes WHERE ticker=$1 ORDER BY ts DESC LIMIT 1"
+
+// Latest ...
+func (s *QuoteStorage) Latest(ticker string) (*quotes.Quote, error) {
+	var q quotes.Quote
+
+	err := s.latestStmt.QueryRow(ticker).Scan(&q.Ticker, &q.BuyPrice, &q.SellPrice, &q.Ts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get latest quote with ticker "+ticker)
+	}
+
+	return &q, nil
+}
+
+const listQuotePartitionsQuery = "SELECT c.relname FROM pg_inherits i " +
+	"JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class p ON p.oid = i.inhparent WHERE p.relname = 'quotes'"
+
+// DeleteBefore удаляет целые дневные партиции, которые закончились до t
+func (s *QuoteStorage) DeleteBefore(t time.Time) (int, error) {
+	rows, err := s.listPartitionsStmt.Query()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list quote partitions")
+	}
+
+	var old []string
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+
+			return 0, errors.Wrap(err, "failed to scan quote partition")
+		}
+
+		if !strings.HasPrefix(name, partitionPrefix) {
+			continue
+		}
+
+		start, err := time.Parse(partitionLayout, strings.TrimPrefix(name, partitionPrefix))
+		if err != nil {
+			continue
+		}
+
+		if !start.Add(day).After(t) {
+			old = append(old, name)
+		}
+	}
+
+	err = rows.Err()
+	rows.Close()
+
+	// без полного списка партиций нельзя решить, какие из них старые
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read quote partitions")
+	}
+
+	for _, name := range old {
+		if _, err := s.db.Session.Exec("DROP TABLE IF EXISTS public." + name); err != nil {
+			return 0, errors.Wrap(err, "can't drop partition "+name)
+		}
+
+		s.mu.Lock()
+		delete(s.partitions, name)
+		s.mu.Unlock()
+	}
+
+	return len(old), nil
+}
@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"authDB/internal/robots"
+	"authDB/internal/strategy"
 	"database/sql"
 	"strconv"
 
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +28,9 @@ type RobotStorage struct {
 	favoriteRobotStmt     *sql.Stmt
 	updateActualRobotStmt *sql.Stmt
 	getActualRobotStmt    *sql.Stmt
+	setDegradedStmt       *sql.Stmt
+	setStatusStmt         *sql.Stmt
+	setPlanReachedStmt    *sql.Stmt
 }
 
 // NewRobotStorage ...
@@ -45,6 +50,9 @@ func NewRobotStorage(db *DB) (*RobotStorage, error) {
 		{Query: favoriteRobotQuery, Dst: &s.favoriteRobotStmt},
 		{Query: updateActualRobotStmtQuery, Dst: &s.updateActualRobotStmt},
 		{Query: getAllNonDeletedRobotsStmtQuery, Dst: &s.getActualRobotStmt},
+		{Query: setDegradedRobotStmtQuery, Dst: &s.setDegradedStmt},
+		{Query: setStatusRobotStmtQuery, Dst: &s.setStatusStmt},
+		{Query: setPlanReachedRobotStmtQuery, Dst: &s.setPlanReachedStmt},
 	}
 
 	if err := s.initStatements(stmts); err != nil {
@@ -55,15 +63,24 @@ func NewRobotStorage(db *DB) (*RobotStorage, error) {
 }
 
 const robotFields = "owner_user_id, parent_robot_id, is_favorite, is_active, ticker, buy_price, sell_price," +
-	"plan_start, plan_end, plan_yield, fact_yield, deals_count, activated_at, deactivated_at, created_at, deleted_at"
+	"plan_start, plan_end, plan_yield, fact_yield, deals_count, activated_at, deactivated_at, created_at, deleted_at," +
+	"strategy, strategy_params, is_degraded, status, status_reason, status_changed_at, quantity," +
+	"stop_loss, stop_loss_percent, take_profit, take_profit_percent, trailing_stop, trailing_stop_percent," +
+	"on_plan_yield, plan_outcome, plan_reached_at, max_loss, max_drawdown, direction, schedule, schedule_exclude"
+
+const selectRobotFields = "SELECT id, " + robotFields + " FROM public.robots "
 
 const createRobotQuery = "INSERT INTO public.robots (" + robotFields + ") " +
-	"VALUES ($1, 0, false, false, $2, $3, $4, $5, $6, $7, 0, 0,  null, null, now(), null)" +
+	"VALUES ($1, 0, false, false, $2, $3, $4, $5, $6, $7, 0, 0,  null, null, now(), null, $8, $9, false, 'draft', '', now(), $10, $11, $12, $13, $14, $15, $16, $17, '', null, $18, $19, $20, $21, $22)" +
 	"RETURNING id;"
 
 // Create ...
 func (s *RobotStorage) Create(rob *robots.Robot) error {
-	err := s.createStmt.QueryRow(rob.OwnerUserID, rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield).Scan(&rob.RobotID)
+	err := s.createStmt.QueryRow(rob.OwnerUserID, rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield,
+		strategyName(rob), rob.StrategyParams, rob.Quantity,
+		rob.StopLoss.Value, rob.StopLoss.Percent, rob.TakeProfit.Value, rob.TakeProfit.Percent,
+		rob.TrailingStop.Value, rob.TrailingStop.Percent, planAction(rob), rob.MaxLoss, rob.MaxDrawdown, direction(rob),
+		rob.Schedule, scheduleExclude(rob)).Scan(&rob.RobotID)
 	if err != nil {
 		return errors.Wrap(err, "failed to create robot")
 	}
@@ -71,21 +88,22 @@ func (s *RobotStorage) Create(rob *robots.Robot) error {
 	return nil
 }
 
-const deleteRobotQuery = "UPDATE public.robots SET deleted_at=now() WHERE id=$1"
+const deleteRobotQuery = "UPDATE public.robots SET deleted_at=now(), is_active=false, " +
+	"status='deleted', status_reason='deleted by user', status_changed_at=now() WHERE id=$1 AND status = ANY($2)"
 
 // Delete ...
 func (s *RobotStorage) Delete(id int) error {
-	_, err := s.deleteStmt.Exec(id)
+	res, err := s.deleteStmt.Exec(id, pq.Array(robots.From(robots.StatusDeleted)))
 
 	if err != nil {
 		idStr := strconv.Itoa(id)
 		return errors.WithMessage(err, "failed to delete robot with id "+idStr)
 	}
 
-	return nil
+	return checkTransition(res, id, robots.StatusDeleted)
 }
 
-const getAllUserRobotsQuery = "SELECT * FROM robots WHERE owner_user_id=$1 AND deleted_at IS NULL"
+const getAllUserRobotsQuery = selectRobotFields + "WHERE owner_user_id=$1 AND deleted_at IS NULL"
 
 // GetAllUserRobots ...
 func (s *RobotStorage) GetAllUserRobots(userID int) ([]*robots.Robot, error) {
@@ -113,7 +131,7 @@ func (s *RobotStorage) GetAllUserRobots(userID int) ([]*robots.Robot, error) {
 	return rbts, rows.Err()
 }
 
-const getAllTickerRobotsStmtQuery = "SELECT * FROM robots WHERE ticker=$1 AND deleted_at IS NULL"
+const getAllTickerRobotsStmtQuery = selectRobotFields + "WHERE ticker=$1 AND deleted_at IS NULL"
 
 // GetAllTickerRobots ...
 func (s *RobotStorage) GetAllTickerRobots(ticker string) ([]*robots.Robot, error) {
@@ -139,7 +157,7 @@ func (s *RobotStorage) GetAllTickerRobots(ticker string) ([]*robots.Robot, error
 	return rbts, rows.Err()
 }
 
-const getRobotStmtQuery = "SELECT * FROM robots WHERE id=$1 AND deleted_at IS NULL"
+const getRobotStmtQuery = selectRobotFields + "WHERE id=$1 AND deleted_at IS NULL"
 
 // GetRobot ...
 func (s *RobotStorage) GetRobot(id int) (*robots.Robot, error) {
@@ -155,57 +173,79 @@ func (s *RobotStorage) GetRobot(id int) (*robots.Robot, error) {
 	return &robot, nil
 }
 
-const activateRobotStmtQuery = "UPDATE public.robots SET is_active=true, activated_at=now() WHERE id=$1 AND (now()<plan_start OR now()>plan_end) AND is_active=false"
+const activateRobotStmtQuery = "UPDATE public.robots SET is_active=true, activated_at=now(), " +
+	"status='scheduled', status_reason='activated by user', status_changed_at=now() WHERE id=$1 AND status = ANY($2)"
 
 // ActivateRobot ...
 func (s *RobotStorage) ActivateRobot(id int) error {
 	idStr := strconv.Itoa(id)
 
-	_, err := s.activateRobotStmt.Exec(id)
+	res, err := s.activateRobotStmt.Exec(id, pq.Array(robots.From(robots.StatusScheduled)))
 	if err != nil {
 		return errors.WithMessage(err, "failed to activate robot with id"+idStr)
 	}
 
-	return nil
+	return checkTransition(res, id, robots.StatusScheduled)
 }
 
-const deactivateRobotStmtQuery = "UPDATE public.robots SET is_active=false, deactivated_at=now() WHERE id=$1 AND (now()<plan_start OR now()>plan_end) AND is_active=true"
+const deactivateRobotStmtQuery = "UPDATE public.robots SET is_active=false, deactivated_at=now(), " +
+	"status='draft', status_reason=$3, status_changed_at=now() WHERE id=$1 AND status = ANY($2)"
 
 // DeactivateRobot ...
-func (s *RobotStorage) DeactivateRobot(id int) error {
+func (s *RobotStorage) DeactivateRobot(id int, reason string) error {
 	idStr := strconv.Itoa(id)
 
-	_, err := s.deactivateRobotStmt.Exec(id)
+	res, err := s.deactivateRobotStmt.Exec(id, pq.Array(robots.From(robots.StatusDraft)), reason)
 	if err != nil {
 		return errors.WithMessage(err, "failed to deactivate robot with id"+idStr)
 	}
 
-	return nil
+	return checkTransition(res, id, robots.StatusDraft)
 }
 
-const updateRobotQuery = "UPDATE public.robots SET ticker=$1, buy_price=$2, sell_price=$3, plan_start=$4, plan_end=$5, plan_yield=$6 WHERE id=$7 AND is_active=false"
+const updateRobotQuery = "UPDATE public.robots SET ticker=$1, buy_price=$2, sell_price=$3, plan_start=$4, plan_end=$5, plan_yield=$6, " +
+	"strategy=$7, strategy_params=$8, quantity=$9, stop_loss=$10, stop_loss_percent=$11, take_profit=$12, take_profit_percent=$13, " +
+	"trailing_stop=$14, trailing_stop_percent=$15, " +
+	"on_plan_yield=$16, plan_outcome='', plan_reached_at=null, max_loss=$17, max_drawdown=$18, direction=$19, schedule=$20, schedule_exclude=$21 WHERE id=$22 AND deleted_at IS NULL"
 
 // Update ...
 func (s *RobotStorage) Update(rob *robots.Robot) error {
 	idStr := strconv.Itoa(rob.RobotID)
 
-	_, err := s.updateRobotStmt.Exec(rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield, rob.RobotID)
+	res, err := s.updateRobotStmt.Exec(rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield,
+		strategyName(rob), rob.StrategyParams, rob.Quantity,
+		rob.StopLoss.Value, rob.StopLoss.Percent, rob.TakeProfit.Value, rob.TakeProfit.Percent,
+		rob.TrailingStop.Value, rob.TrailingStop.Percent, planAction(rob), rob.MaxLoss, rob.MaxDrawdown, direction(rob),
+		rob.Schedule, scheduleExclude(rob), rob.RobotID)
 	if err != nil {
 		return errors.WithMessage(err, "failed to update robot with id"+idStr)
 	}
 
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "can't get affected rows")
+	}
+
+	if n == 0 {
+		return errors.Wrap(sql.ErrNoRows, "no robot to update with id "+idStr)
+	}
+
 	return nil
 }
 
 const favoriteRobotQuery = "INSERT INTO public.robots (" + robotFields + ") " +
-	"VALUES ($1, $2, true, false, $3, $4, $5, $6, $7, $8, 0, 0,  null, null, now(), null)" +
+	"VALUES ($1, $2, true, false, $3, $4, $5, $6, $7, $8, 0, 0,  null, null, now(), null, $9, $10, false, 'draft', '', now(), $11, $12, $13, $14, $15, $16, $17, $18, '', null, $19, $20, $21, $22, $23)" +
 	"RETURNING id;"
 
 // FavoriteRobot ...
 func (s *RobotStorage) FavoriteRobot(rob *robots.Robot) error {
 	idStr := strconv.Itoa(rob.RobotID)
 
-	err := s.favoriteRobotStmt.QueryRow(rob.OwnerUserID, rob.ParentRobotID, rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield).Scan(&rob.RobotID)
+	err := s.favoriteRobotStmt.QueryRow(rob.OwnerUserID, rob.ParentRobotID, rob.Ticker, rob.BuyPrice, rob.SellPrice, rob.PlanStart.Time, rob.PlanEnd.Time, rob.PlanYield,
+		strategyName(rob), rob.StrategyParams, rob.Quantity,
+		rob.StopLoss.Value, rob.StopLoss.Percent, rob.TakeProfit.Value, rob.TakeProfit.Percent,
+		rob.TrailingStop.Value, rob.TrailingStop.Percent, planAction(rob), rob.MaxLoss, rob.MaxDrawdown, direction(rob),
+		rob.Schedule, scheduleExclude(rob)).Scan(&rob.RobotID)
 	if err != nil {
 		return errors.WithMessage(err, "failed to make favorite robot with id"+idStr)
 	}
@@ -247,7 +287,7 @@ func (s *RobotStorage) UpdateActual(rob *robots.Robot) error {
 	return nil
 }
 
-const getAllNonDeletedRobotsStmtQuery = "SELECT * FROM public.robots WHERE deleted_at IS NULL"
+const getAllNonDeletedRobotsStmtQuery = selectRobotFields + "WHERE deleted_at IS NULL"
 
 //GetAllNonDeletedRobots ...
 func (s *RobotStorage) GetAllNonDeletedRobots() ([]*robots.Robot, error) { // nolint
@@ -272,8 +312,104 @@ func (s *RobotStorage) GetAllNonDeletedRobots() ([]*robots.Robot, error) { // no
 	return rbts, rows.Err()
 }
 
+const setDegradedRobotStmtQuery = "UPDATE public.robots SET is_degraded=$1 WHERE id=$2"
+
+// SetDegraded ...
+func (s *RobotStorage) SetDegraded(id int, degraded bool) error {
+	idStr := strconv.Itoa(id)
+
+	_, err := s.setDegradedStmt.Exec(degraded, id)
+	if err != nil {
+		return errors.WithMessage(err, "failed to set degraded robot with id"+idStr)
+	}
+
+	return nil
+}
+
+const setStatusRobotStmtQuery = "UPDATE public.robots SET status=$1, status_reason=$2, status_changed_at=now(), is_active=$3 " +
+	"WHERE id=$4 AND status = ANY($5)"
+
+// SetStatus ...
+func (s *RobotStorage) SetStatus(id int, to robots.Status, reason string) error {
+	idStr := strconv.Itoa(id)
+
+	res, err := s.setStatusStmt.Exec(to, reason, to.IsLive(), id, pq.Array(robots.From(to)))
+	if err != nil {
+		return errors.WithMessage(err, "failed to set status robot with id"+idStr)
+	}
+
+	return checkTransition(res, id, to)
+}
+
+// checkTransition превращает пустой UPDATE в ErrBadTransition
+func checkTransition(res sql.Result, id int, to robots.Status) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "can't get affected rows")
+	}
+
+	if n == 0 {
+		return errors.Wrapf(robots.ErrBadTransition, "robot %d can't move to %s", id, to)
+	}
+
+	return nil
+}
+
+const setPlanReachedRobotStmtQuery = "UPDATE public.robots SET plan_outcome=$1, plan_reached_at=now() " +
+	"WHERE id=$2 AND plan_reached_at IS NULL"
+
+// SetPlanReached ...
+func (s *RobotStorage) SetPlanReached(id int, outcome string) error {
+	idStr := strconv.Itoa(id)
+
+	_, err := s.setPlanReachedStmt.Exec(outcome, id)
+	if err != nil {
+		return errors.WithMessage(err, "failed to set plan outcome of robot with id"+idStr)
+	}
+
+	return nil
+}
+
 func scanRobot(scanner sqlScanner, r *robots.Robot) error {
 	return scanner.Scan(&r.RobotID, &r.OwnerUserID, &r.ParentRobotID, &r.IsFavorite, &r.IsActive, &r.Ticker,
 		&r.BuyPrice, &r.SellPrice, &r.PlanStart, &r.PlanEnd, &r.PlanYield, &r.FactYield, &r.DealsCount,
-		&r.ActivatedAt, &r.DeactivatedAt, &r.CreatedAt, &r.DeletedAt)
+		&r.ActivatedAt, &r.DeactivatedAt, &r.CreatedAt, &r.DeletedAt, &r.Strategy, &r.StrategyParams,
+		&r.IsDegraded, &r.Status, &r.StatusReason, &r.StatusChangedAt, &r.Quantity,
+		&r.StopLoss.Value, &r.StopLoss.Percent, &r.TakeProfit.Value, &r.TakeProfit.Percent,
+		&r.TrailingStop.Value, &r.TrailingStop.Percent,
+		&r.OnPlanYield, &r.PlanOutcome, &r.PlanReachedAt, &r.MaxLoss, &r.MaxDrawdown, &r.Direction,
+		&r.Schedule, pq.Array(&r.ScheduleExclude))
+}
+
+func strategyName(r *robots.Robot) string {
+	if r.Strategy == "" {
+		return strategy.Default
+	}
+
+	return r.Strategy
+}
+
+func planAction(r *robots.Robot) string {
+	if r.OnPlanYield == "" {
+		return string(robots.PlanNotify)
+	}
+
+	return string(r.OnPlanYield)
+}
+
+func direction(r *robots.Robot) string {
+	if r.Direction == "" {
+		return string(robots.DirectionLong)
+	}
+
+	return string(r.Direction)
+}
+
+// scheduleExclude пустой список вместо NULL для колонки NOT NULL
+func scheduleExclude(r *robots.Robot) interface{} {
+	if r.ScheduleExclude == nil {
+		return pq.Array([]string{})
+	}
+
+	return pq.Array(r.ScheduleExclude)
 }
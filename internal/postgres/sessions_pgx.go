@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"authDB/internal/clock"
 	"authDB/internal/sessions"
 	"database/sql"
 	"strconv"
@@ -96,8 +97,9 @@ func (s *SessionStorage) Update(token string, id int) error {
 
 	sesDuration := min * time.Minute
 	idStr := strconv.Itoa(id)
+	now := clock.Now()
 
-	if _, err := s.update.Exec(token, time.Now(), time.Now().Add(sesDuration), id); err != nil {
+	if _, err := s.update.Exec(token, now, now.Add(sesDuration), id); err != nil {
 		return errors.Wrap(err, "can not exec query with userID"+idStr)
 	}
 
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"authDB/internal/robots"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var _ robots.States = &StateStorage{}
+
+// StateStorage ...
+type StateStorage struct {
+	statementStorage
+
+	saveStmt *sql.Stmt
+	getStmt  *sql.Stmt
+}
+
+// NewStateStorage ...
+func NewStateStorage(db *DB) (*StateStorage, error) {
+	s := &StateStorage{statementStorage: newStatementsStorage(db)}
+
+	stmts := []stmt{
+		{Query: saveStateQuery, Dst: &s.saveStmt},
+		{Query: getStateQuery, Dst: &s.getStmt},
+	}
+
+	if err := s.initStatements(stmts); err != nil {
+		return nil, errors.Wrap(err, "can't init statements")
+	}
+
+	return s, nil
+}
+
+const stateFields = "robot_id, side, entry_price, entry_time, last_tick_ts, updated_at, quantity, high_water, peak_equity, " +
+	"order_seq, pending_order"
+
+const saveStateQuery = "INSERT INTO public.robot_states (" + stateFields + ") " +
+	"VALUES ($1, $2, $3, $4, $5, now(), $6, $7, $8, $9, $10) " +
+	"ON CONFLICT (robot_id) DO UPDATE SET side=EXCLUDED.side, entry_price=EXCLUDED.entry_price, " +
+	"entry_time=EXCLUDED.entry_time, last_tick_ts=EXCLUDED.last_tick_ts, quantity=EXCLUDED.quantity, " +
+	"high_water=EXCLUDED.high_water, peak_equity=EXCLUDED.peak_equity, order_seq=EXCLUDED.order_seq, " +
+	"pending_order=EXCLUDED.pending_order, updated_at=now()"
+
+// SaveState ...
+func (s *StateStorage) SaveState(st *robots.State) error {
+	args, err := stateArgs(st)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.saveStmt.Exec(args...); err != nil {
+		return errors.WithMessage(err, "failed to save state of robot "+strconv.Itoa(st.RobotID))
+	}
+
+	return nil
+}
+
+const getStateQuery = "SELECT " + stateFields + " FROM public.robot_states WHERE robot_id=$1"
+
+// GetState ...
+func (s *StateStorage) GetState(robotID int) (*robots.State, error) {
+	var st robots.State
+
+	err := scanState(s.getStmt.QueryRow(robotID), &st)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "can not scan state of robot "+strconv.Itoa(robotID))
+	}
+
+	return &st, nil
+}
+
+func scanState(scanner sqlScanner, st *robots.State) error {
+	var pending []byte
+
+	err := scanner.Scan(&st.RobotID, &st.Side, &st.EntryPrice, &st.EntryTime, &st.LastTickTs, &st.UpdatedAt, &st.Quantity, &st.HighWater,
+		&st.PeakEquity, &st.OrderSeq, &pending)
+	if err != nil || pending == nil {
+		return err
+	}
+
+	st.Pending = &robots.PendingOrder{}
+
+	return errors.Wrap(json.Unmarshal(pending, st.Pending), "bad pending order")
+}
+
+// stateArgs аргументы saveStateQuery, незаписанная заявка хранится в jsonb
+func stateArgs(st *robots.State) ([]interface{}, error) {
+	// без заявки в базу уходит NULL, пустой []byte jsonb не примет
+	var pending interface{}
+
+	if st.Pending != nil {
+		data, err := json.Marshal(st.Pending)
+		if err != nil {
+			return nil, errors.Wrap(err, "can't marshal pending order of robot "+strconv.Itoa(st.RobotID))
+		}
+
+		pending = data
+	}
+
+	return []interface{}{st.RobotID, st.Side, st.EntryPrice, st.EntryTime, st.LastTickTs, st.Quantity, st.HighWater, st.PeakEquity,
+		st.OrderSeq, pending}, nil
+}
@@ -38,15 +38,15 @@ func NewUserStorage(db *DB) (*UserStorage, error) {
 	return s, nil
 }
 
-const userFields = "firstname, lastname, birthday, email, password, created_at, updated_at"
+const userFields = "firstname, lastname, birthday, email, password, created_at, updated_at, is_admin, timezone"
 
-const createUserQuery = "INSERT INTO public.users (" + userFields + ") VALUES ($1, $2, $3, $4, $5, now(), now()) RETURNING id"
+const createUserQuery = "INSERT INTO public.users (" + userFields + ") VALUES ($1, $2, $3, $4, $5, now(), now(), false, $6) RETURNING id"
 
 // Create ...
 func (s *UserStorage) Create(u *user.User) error {
 	idStr := strconv.Itoa(u.ID)
 
-	if err := s.createStmt.QueryRow(&u.FirstName, &u.LastName, &u.Birthday, &u.Email, &u.Password).Scan(&u.ID); err != nil {
+	if err := s.createStmt.QueryRow(&u.FirstName, &u.LastName, &u.Birthday, &u.Email, &u.Password, timezoneName(u)).Scan(&u.ID); err != nil {
 		return errors.WithMessage(err, "can not exec query with userID"+idStr)
 	}
 
@@ -70,15 +70,15 @@ func (s *UserStorage) Find(id int) (*user.User, error) {
 }
 
 const updateUserQuery = "UPDATE public.users " +
-	" SET firstname=$1, lastname=$2, birthday=$3, email=$4, password=$5, updated_at=now()" +
-	"WHERE id=$6" +
+	" SET firstname=$1, lastname=$2, birthday=$3, email=$4, password=$5, timezone=$6, updated_at=now()" +
+	"WHERE id=$7" +
 	"RETURNING id"
 
 // Update ...
 func (s *UserStorage) Update(u *user.User) error {
 	idStr := strconv.Itoa(u.ID)
 
-	if err := s.updateStmt.QueryRow(u.FirstName, u.LastName, u.Birthday, u.Email, u.Password, u.ID).Scan(&u.ID); err != nil {
+	if err := s.updateStmt.QueryRow(u.FirstName, u.LastName, u.Birthday, u.Email, u.Password, timezoneName(u), u.ID).Scan(&u.ID); err != nil {
 		return errors.WithMessage(err, "can not update user with id"+idStr)
 	}
 
@@ -100,5 +100,13 @@ func (s *UserStorage) FindByEmail(email string) (*user.User, error) {
 }
 
 func scanUser(scanner sqlScanner, u *user.User) error {
-	return scanner.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Birthday, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt)
+	return scanner.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Birthday, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt, &u.IsAdmin, &u.Timezone)
+}
+
+func timezoneName(u *user.User) string {
+	if u.Timezone == "" {
+		return "UTC"
+	}
+
+	return u.Timezone
 }
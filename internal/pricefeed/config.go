@@ -0,0 +1,112 @@
+package pricefeed
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Режимы генерации котировок
+const (
+	ModeRandom = "random"
+	ModeSine   = "sine"
+	ModeScript = "script"
+	ModeCSV    = "csv"
+)
+
+// Duration time.Duration, который в json пишется строкой вида "1s"
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON ...
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Wrap(err, "duration should be a string")
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Wrap(err, "bad duration")
+	}
+
+	d.Duration = v
+
+	return nil
+}
+
+// TickerConfig настройки котировок одного тикера
+type TickerConfig struct {
+	Mode string `json:"mode"`
+	// Interval пауза между котировками, для csv берется из файла
+	Interval Duration `json:"interval"`
+	// Spread разница между ценой покупки и продажи
+	Spread float64 `json:"spread"`
+
+	// random: старт и максимальный шаг случайного блуждания
+	Start float64 `json:"start"`
+	Step  float64 `json:"step"`
+
+	// sine: центр, амплитуда и период синусоиды
+	Center    float64  `json:"center"`
+	Amplitude float64  `json:"amplitude"`
+	Period    Duration `json:"period"`
+
+	// script: цены по порядку, после последней начинается заново
+	Prices []float64 `json:"prices"`
+
+	// csv: файл в формате backtest.ReadCSV и ускорение воспроизведения
+	File  string  `json:"file"`
+	Speed float64 `json:"speed"`
+}
+
+// Config настройки сервера котировок
+type Config struct {
+	Addr    string                  `json:"addr"`
+	Default TickerConfig            `json:"default"`
+	Tickers map[string]TickerConfig `json:"tickers"`
+}
+
+// DefaultConfig случайное блуждание для любого тикера
+func DefaultConfig() Config {
+	return Config{
+		Addr: ":5000",
+		Default: TickerConfig{
+			Mode:     ModeRandom,
+			Interval: Duration{time.Second},
+			Spread:   0.1,
+			Start:    10,
+			Step:     0.5,
+		},
+		Tickers: map[string]TickerConfig{},
+	}
+}
+
+// LoadConfig читает настройки из json файла поверх DefaultConfig
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, errors.Wrap(err, "can't read config")
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, errors.Wrap(err, "can't parse config")
+	}
+
+	return cfg, nil
+}
+
+// ticker настройки тикера или настройки по умолчанию
+func (c Config) ticker(name string) TickerConfig {
+	if tc, ok := c.Tickers[name]; ok {
+		return tc
+	}
+
+	return c.Default
+}
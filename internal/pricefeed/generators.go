@@ -0,0 +1,149 @@
+package pricefeed
+
+import (
+	"authDB/internal/backtest"
+	"authDB/internal/fintech"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+// Generator выдает очередную котировку и паузу перед ее отправкой
+type Generator interface {
+	Next() (data *fintech.PriceResponse, wait time.Duration, ok bool)
+}
+
+// NewGenerator создает генератор для одного потока по настройкам тикера
+func NewGenerator(tc TickerConfig) (Generator, error) {
+	switch tc.Mode {
+	case ModeRandom, "":
+		return &randomWalk{cfg: tc, price: tc.Start, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}, nil // nolint
+	case ModeSine:
+		if tc.Period.Duration <= 0 {
+			return nil, errors.New("sine needs period")
+		}
+
+		return &sine{cfg: tc, started: time.Now()}, nil
+	case ModeScript:
+		if len(tc.Prices) == 0 {
+			return nil, errors.New("script needs prices")
+		}
+
+		return &script{cfg: tc}, nil
+	case ModeCSV:
+		return newReplay(tc)
+	}
+
+	return nil, errors.Errorf("unknown mode %q", tc.Mode)
+}
+
+// quote котировка вокруг средней цены со спредом, время - текущее
+func quote(mid, spread float64) *fintech.PriceResponse {
+	ts, _ := ptypes.TimestampProto(time.Now()) // nolint
+
+	return &fintech.PriceResponse{
+		BuyPrice:  round(mid + spread/2),
+		SellPrice: round(mid - spread/2),
+		Ts:        ts,
+	}
+}
+
+func round(v float64) float64 {
+	const cents = 100
+
+	return math.Round(v*cents) / cents
+}
+
+type randomWalk struct {
+	cfg   TickerConfig
+	price float64
+	rnd   *rand.Rand
+}
+
+func (g *randomWalk) Next() (*fintech.PriceResponse, time.Duration, bool) {
+	g.price += (g.rnd.Float64()*2 - 1) * g.cfg.Step
+	if g.price < g.cfg.Spread {
+		g.price = g.cfg.Spread
+	}
+
+	return quote(g.price, g.cfg.Spread), g.cfg.Interval.Duration, true
+}
+
+type sine struct {
+	cfg     TickerConfig
+	started time.Time
+}
+
+func (g *sine) Next() (*fintech.PriceResponse, time.Duration, bool) {
+	phase := 2 * math.Pi * float64(time.Since(g.started)) / float64(g.cfg.Period.Duration)
+	mid := g.cfg.Center + g.cfg.Amplitude*math.Sin(phase)
+
+	return quote(mid, g.cfg.Spread), g.cfg.Interval.Duration, true
+}
+
+type script struct {
+	cfg TickerConfig
+	pos int
+}
+
+func (g *script) Next() (*fintech.PriceResponse, time.Duration, bool) {
+	mid := g.cfg.Prices[g.pos%len(g.cfg.Prices)]
+	g.pos++
+
+	return quote(mid, g.cfg.Spread), g.cfg.Interval.Duration, true
+}
+
+// replay воспроизводит csv с исходными паузами, деленными на Speed
+type replay struct {
+	ticks []*fintech.PriceResponse
+	speed float64
+	pos   int
+}
+
+func newReplay(tc TickerConfig) (*replay, error) {
+	f, err := os.Open(tc.File)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't open csv")
+	}
+	defer f.Close()
+
+	ticks, err := backtest.ReadCSV(f)
+	if err != nil {
+		return nil, err
+	}
+
+	speed := tc.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &replay{ticks: ticks, speed: speed}, nil
+}
+
+func (g *replay) Next() (*fintech.PriceResponse, time.Duration, bool) {
+	if g.pos >= len(g.ticks) {
+		return nil, 0, false
+	}
+
+	var wait time.Duration
+
+	if g.pos > 0 {
+		prev, _ := ptypes.Timestamp(g.ticks[g.pos-1].Ts) // nolint
+		cur, _ := ptypes.Timestamp(g.ticks[g.pos].Ts)    // nolint
+		wait = time.Duration(float64(cur.Sub(prev)) / g.speed)
+	}
+
+	tick := g.ticks[g.pos]
+	g.pos++
+
+	// время котировки текущее, иначе движок отбросит повторное воспроизведение как старые котировки
+	data := quote(0, 0)
+	data.BuyPrice = tick.BuyPrice
+	data.SellPrice = tick.SellPrice
+
+	return data, wait, true
+}
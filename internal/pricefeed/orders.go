@@ -0,0 +1,225 @@
+package pricefeed
+
+import (
+	"authDB/internal/fintech"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	fillCheckInterval = 100 * time.Millisecond
+	orderTTL          = 30 * time.Second
+)
+
+// exchange простая книга заявок учебной биржи: лимитная заявка исполняется целиком,
+// как только последняя котировка тикера ее пересекает. Повторная заявка с тем же ClientOrderId
+// не выставляется заново, клиент получает первую
+type exchange struct {
+	mu       sync.Mutex
+	quotes   map[string]*fintech.PriceResponse
+	orders   map[string]*order
+	byClient map[string]*order
+	seq      int
+}
+
+type order struct {
+	pb       *fintech.Order
+	canceled chan struct{}
+}
+
+func newExchange() *exchange {
+	return &exchange{
+		quotes:   make(map[string]*fintech.PriceResponse),
+		orders:   make(map[string]*order),
+		byClient: make(map[string]*order),
+	}
+}
+
+func (e *exchange) setQuote(ticker string, data *fintech.PriceResponse) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.quotes[ticker] = data
+}
+
+func (e *exchange) place(req *fintech.PlaceOrderRequest) *order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if o, ok := e.byClient[req.ClientOrderId]; ok && req.ClientOrderId != "" {
+		return o
+	}
+
+	e.seq++
+
+	o := &order{
+		pb: &fintech.Order{
+			OrderId:       strconv.Itoa(e.seq),
+			ClientOrderId: req.ClientOrderId,
+			Ticker:        req.Ticker,
+			Side:          req.Side,
+			Quantity:      req.Quantity,
+			LimitPrice:    req.LimitPrice,
+			State:         fintech.OrderState_NEW,
+			CreatedAt:     ptypes.TimestampNow(),
+		},
+		canceled: make(chan struct{}),
+	}
+
+	switch {
+	case req.Ticker == "":
+		o.pb.State = fintech.OrderState_REJECTED
+		o.pb.Reason = "empty ticker"
+	case req.Quantity <= 0:
+		o.pb.State = fintech.OrderState_REJECTED
+		o.pb.Reason = "quantity should be positive"
+	case req.LimitPrice <= 0:
+		o.pb.State = fintech.OrderState_REJECTED
+		o.pb.Reason = "limit price should be positive"
+	}
+
+	e.orders[o.pb.OrderId] = o
+
+	if req.ClientOrderId != "" {
+		e.byClient[req.ClientOrderId] = o
+	}
+
+	return o
+}
+
+// tryFill исполняет заявку, если котировка ее пересекает; без котировок исполняет по лимиту
+func (e *exchange) tryFill(o *order) (*fintech.Fill, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if o.pb.State != fintech.OrderState_NEW {
+		return nil, false
+	}
+
+	price := o.pb.LimitPrice
+
+	if q, ok := e.quotes[o.pb.Ticker]; ok {
+		switch {
+		case o.pb.Side == fintech.Side_BUY && q.BuyPrice <= o.pb.LimitPrice:
+			price = q.BuyPrice
+		case o.pb.Side == fintech.Side_SELL && q.SellPrice >= o.pb.LimitPrice:
+			price = q.SellPrice
+		default:
+			return nil, false
+		}
+	}
+
+	fill := &fintech.Fill{Quantity: o.pb.Quantity, Price: price, Ts: ptypes.TimestampNow()}
+
+	o.pb.Fills = append(o.pb.Fills, fill)
+	o.pb.FilledQuantity = o.pb.Quantity
+	o.pb.AvgFillPrice = price
+	o.pb.State = fintech.OrderState_FILLED
+
+	return fill, true
+}
+
+func (e *exchange) cancel(id, reason string) (*fintech.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, ok := e.orders[id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	if o.pb.State == fintech.OrderState_NEW || o.pb.State == fintech.OrderState_PARTIALLY_FILLED {
+		o.pb.State = fintech.OrderState_CANCELED
+		o.pb.Reason = reason
+		close(o.canceled)
+	}
+
+	return proto.Clone(o.pb).(*fintech.Order), nil
+}
+
+func (e *exchange) snapshot(o *order) *fintech.Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return proto.Clone(o.pb).(*fintech.Order)
+}
+
+// PlaceOrder ...
+func (s *Server) PlaceOrder(req *fintech.PlaceOrderRequest, stream fintech.TradingService_PlaceOrderServer) error {
+	o := s.exchange.place(req)
+
+	if err := stream.Send(&fintech.OrderEvent{Order: s.exchange.snapshot(o)}); err != nil {
+		return errors.Wrap(err, "can't send order event")
+	}
+
+	// отклоненная заявка или повтор уже исполненной либо снятой: первое событие окончательное
+	if s.exchange.snapshot(o).State != fintech.OrderState_NEW {
+		return nil
+	}
+
+	return s.watchOrder(stream.Context(), o, stream)
+}
+
+func (s *Server) watchOrder(ctx context.Context, o *order, stream fintech.TradingService_PlaceOrderServer) error {
+	check := time.NewTicker(fillCheckInterval)
+	defer check.Stop()
+
+	expire := time.After(orderTTL)
+
+	for {
+		if fill, ok := s.exchange.tryFill(o); ok {
+			return sendEvent(stream, &fintech.OrderEvent{Order: s.exchange.snapshot(o), Fill: fill})
+		}
+
+		select {
+		case <-ctx.Done():
+			_, err := s.exchange.cancel(o.pb.OrderId, "client gone")
+
+			return err
+		case <-o.canceled:
+			return sendEvent(stream, &fintech.OrderEvent{Order: s.exchange.snapshot(o)})
+		case <-expire:
+			canceled, err := s.exchange.cancel(o.pb.OrderId, "expired")
+			if err != nil {
+				return err
+			}
+
+			return sendEvent(stream, &fintech.OrderEvent{Order: canceled})
+		case <-check.C:
+		}
+	}
+}
+
+func sendEvent(stream fintech.TradingService_PlaceOrderServer, ev *fintech.OrderEvent) error {
+	if err := stream.Send(ev); err != nil {
+		return errors.Wrap(err, "can't send order event")
+	}
+
+	return nil
+}
+
+// CancelOrder ...
+func (s *Server) CancelOrder(ctx context.Context, req *fintech.CancelOrderRequest) (*fintech.Order, error) {
+	return s.exchange.cancel(req.OrderId, "canceled by client")
+}
+
+// OrderStatus ...
+func (s *Server) OrderStatus(ctx context.Context, req *fintech.OrderStatusRequest) (*fintech.Order, error) {
+	s.exchange.mu.Lock()
+	o, ok := s.exchange.orders[req.OrderId]
+	s.exchange.mu.Unlock()
+
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	return s.exchange.snapshot(o), nil
+}
@@ -0,0 +1,87 @@
+package pricefeed
+
+import (
+	"authDB/internal/fintech"
+	"context"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var _ fintech.TradingServiceServer = &Server{}
+
+// Server учебная биржа: отдает котировки по настройкам тикеров и исполняет заявки по ним
+type Server struct {
+	cfg      Config
+	exchange *exchange
+}
+
+// NewServer ...
+func NewServer(cfg Config) *Server {
+	return &Server{cfg: cfg, exchange: newExchange()}
+}
+
+// Price ...
+func (s *Server) Price(req *fintech.PriceRequest, stream fintech.TradingService_PriceServer) error {
+	gen, err := NewGenerator(s.cfg.ticker(req.Ticker))
+	if err != nil {
+		return errors.WithMessage(err, "bad config for ticker "+req.Ticker)
+	}
+
+	ctx := stream.Context()
+
+	for {
+		data, wait, ok := gen.Next()
+		if !ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+
+		s.exchange.setQuote(req.Ticker, data)
+
+		if err := stream.Send(data); err != nil {
+			return errors.Wrap(err, "can't send price")
+		}
+	}
+}
+
+// Register регистрирует сервер котировок на grpc сервере
+func (s *Server) Register(srv *grpc.Server) {
+	fintech.RegisterTradingServiceServer(srv, s)
+}
+
+const bufSize = 1 << 20
+
+// Dial поднимает сервер в памяти через bufconn и возвращает клиента к нему,
+// stop останавливает сервер и закрывает соединение
+func Dial(s *Server) (client fintech.TradingServiceClient, stop func(), err error) {
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	s.Register(srv)
+
+	go srv.Serve(lis) // nolint
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		srv.Stop()
+
+		return nil, nil, errors.Wrap(err, "can't dial bufconn")
+	}
+
+	stop = func() {
+		conn.Close()
+		srv.Stop()
+	}
+
+	return fintech.NewTradingServiceClient(conn), stop, nil
+}
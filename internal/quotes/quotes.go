@@ -0,0 +1,60 @@
+package quotes
+
+import (
+	"authDB/internal/clock"
+	"authDB/internal/fintech"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/shopspring/decimal"
+)
+
+// Quote котировка тикера, полученная от биржи
+type Quote struct {
+	Ticker    string
+	BuyPrice  decimal.Decimal
+	SellPrice decimal.Decimal
+	Ts        time.Time
+}
+
+// Quotes хранилище записанных котировок
+type Quotes interface {
+	SaveBatch(qs []*Quote) error
+	Find(ticker string, from, to time.Time) ([]*Quote, error)
+	// Latest последняя записанная котировка тикера, nil без ошибки, если котировок нет
+	Latest(ticker string) (*Quote, error)
+	// DeleteBefore удаляет котировки старше t и возвращает, сколько партиций удалено
+	DeleteBefore(t time.Time) (int, error)
+}
+
+// FromPrice ...
+func FromPrice(ticker string, data *fintech.PriceResponse) *Quote {
+	q := &Quote{
+		Ticker:    ticker,
+		BuyPrice:  decimal.NewFromFloat(data.GetBuyPrice()),
+		SellPrice: decimal.NewFromFloat(data.GetSellPrice()),
+		Ts:        clock.Now(),
+	}
+
+	if data.GetTs() != nil {
+		ts, err := ptypes.Timestamp(data.GetTs())
+		if err == nil {
+			q.Ts = ts
+		}
+	}
+
+	return q
+}
+
+// ToPrice обратное к FromPrice, нужно для бэктеста по записанной истории
+func (q *Quote) ToPrice() *fintech.PriceResponse {
+	ts, err := ptypes.TimestampProto(q.Ts)
+	if err != nil {
+		ts = nil
+	}
+
+	buy, _ := q.BuyPrice.Float64()
+	sell, _ := q.SellPrice.Float64()
+
+	return &fintech.PriceResponse{BuyPrice: buy, SellPrice: sell, Ts: ts}
+}
@@ -0,0 +1,105 @@
+package quotes
+
+import (
+	"authDB/internal/clock"
+	"authDB/internal/fintech"
+	"authDB/pkg/logger"
+	"context"
+	"time"
+)
+
+// Config настройки записи котировок
+type Config struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	// Retention сколько хранить котировки, 0 - хранить всегда
+	Retention time.Duration
+}
+
+// Recorder пишет котировки в хранилище пачками
+type Recorder struct {
+	logger logger.Logger
+	repo   Quotes
+	cfg    Config
+	in     chan *Quote
+}
+
+// NewRecorder ...
+func NewRecorder(logger logger.Logger, repo Quotes, cfg Config) *Recorder {
+	return &Recorder{
+		logger: logger,
+		repo:   repo,
+		cfg:    cfg,
+		in:     make(chan *Quote, cfg.BatchSize*2),
+	}
+}
+
+// Record не блокирует поток котировок: если запись не успевает, котировка теряется
+func (r *Recorder) Record(ticker string, data *fintech.PriceResponse) {
+	select {
+	case r.in <- FromPrice(ticker, data):
+	default:
+		r.logger.Warnf("quote recorder is too slow, tick with ticker:%s dropped", ticker)
+	}
+}
+
+// Run копит котировки и сбрасывает их по размеру пачки или по таймеру, пока не отменен контекст
+func (r *Recorder) Run(ctx context.Context) {
+	flush := time.NewTicker(r.cfg.FlushInterval)
+	defer flush.Stop()
+
+	retention := time.NewTicker(time.Hour)
+	defer retention.Stop()
+
+	batch := make([]*Quote, 0, r.cfg.BatchSize)
+
+	r.cleanup()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.flush(batch)
+
+			return
+		case q := <-r.in:
+			batch = append(batch, q)
+
+			if len(batch) >= r.cfg.BatchSize {
+				batch = r.flush(batch)
+			}
+		case <-flush.C:
+			batch = r.flush(batch)
+		case <-retention.C:
+			r.cleanup()
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []*Quote) []*Quote {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := r.repo.SaveBatch(batch); err != nil {
+		r.logger.Errorf("failed to save %d quotes %s", len(batch), err)
+	}
+
+	return batch[:0]
+}
+
+func (r *Recorder) cleanup() {
+	if r.cfg.Retention == 0 {
+		return
+	}
+
+	n, err := r.repo.DeleteBefore(clock.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		r.logger.Errorf("failed to apply quotes retention %s", err)
+
+		return
+	}
+
+	if n > 0 {
+		r.logger.Infof("quotes retention dropped %d partitions", n)
+	}
+}
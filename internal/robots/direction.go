@@ -0,0 +1,101 @@
+package robots
+
+import (
+	"authDB/internal/strategy"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// Direction в какую сторону робот может открывать позиции
+type Direction string
+
+// Направления торговли
+const (
+	// DirectionLong покупает по BuyPrice и продает по SellPrice
+	DirectionLong Direction = "long"
+	// DirectionShort продает в шорт по SellPrice и откупает по BuyPrice
+	DirectionShort Direction = "short"
+	// DirectionBoth открывает позицию в ту сторону, чей порог сработал первым
+	DirectionBoth Direction = "both"
+)
+
+// ParseDirection пустое направление означает DirectionLong
+func ParseDirection(s string) (Direction, error) {
+	switch d := Direction(s); d {
+	case "":
+		return DirectionLong, nil
+	case DirectionLong, DirectionShort, DirectionBoth:
+		return d, nil
+	}
+
+	return "", errors.New("bad direction")
+}
+
+// Directions все направления для формы создания
+func Directions() []Direction {
+	return []Direction{DirectionLong, DirectionShort, DirectionBoth}
+}
+
+// Allows можно ли открыть позицию side
+func (d Direction) Allows(side strategy.Side) bool {
+	switch side {
+	case strategy.Long:
+		return d == "" || d == DirectionLong || d == DirectionBoth
+	case strategy.Short:
+		return d == DirectionShort || d == DirectionBoth
+	case strategy.Flat:
+	}
+
+	return false
+}
+
+// CheckDirection проверяет, что пороги цен имеют смысл для направления:
+// выход из позиции должен быть по лучшей цене, чем вход
+func CheckDirection(rob Robot) error {
+	d, err := ParseDirection(string(rob.Direction))
+	if err != nil {
+		return err
+	}
+
+	if rob.SellPrice.GreaterThan(rob.BuyPrice) {
+		return nil
+	}
+
+	switch d {
+	case DirectionShort:
+		return errors.New("short robot should sell above buy price")
+	case DirectionBoth:
+		return errors.New("sell price should be above buy price for both directions")
+	case DirectionLong:
+	}
+
+	return errors.New("long robot should buy below sell price")
+}
+
+// DirectionChanged меняет ли обновление направление или пороги цен. Роботы, созданные до
+// направлений, могут иметь BuyPrice не ниже SellPrice, поэтому при обновлении CheckDirection
+// запускается только вместе с этими изменениями, и такие роботы можно править, не трогая пороги
+func DirectionChanged(old, rob Robot) bool {
+	was, _ := ParseDirection(string(old.Direction)) // nolint
+	now, _ := ParseDirection(string(rob.Direction)) // nolint
+
+	return was != now || !old.BuyPrice.Equal(rob.BuyPrice) || !old.SellPrice.Equal(rob.SellPrice)
+}
+
+// EntryAmount сколько денег нужно на вход в позицию по порогам робота,
+// для обоих направлений берется большая из сумм
+func (r *Robot) EntryAmount() decimal.Decimal {
+	amount := decimal.Zero
+	qty := decimal.NewFromInt(int64(r.Quantity))
+
+	if r.Direction.Allows(strategy.Long) {
+		amount = r.BuyPrice.Mul(qty)
+	}
+
+	if r.Direction.Allows(strategy.Short) {
+		amount = decimal.Max(amount, r.SellPrice.Mul(qty))
+	}
+
+	return amount
+}
@@ -0,0 +1,77 @@
+package robots
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Level уровень выхода от цены входа: абсолютный отступ или процент от входа.
+// Нулевой уровень выключен
+type Level struct {
+	Value   decimal.Decimal
+	Percent bool
+}
+
+// ParseLevel разбирает "5" как отступ 5 от цены входа и "5%" как 5 процентов от нее
+func ParseLevel(s string) (Level, error) {
+	var l Level
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return l, nil
+	}
+
+	if strings.HasSuffix(s, "%") {
+		l.Percent = true
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	}
+
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return Level{}, errors.New("bad level " + s)
+	}
+
+	l.Value = v
+
+	return l, l.Validate()
+}
+
+// Validate ...
+func (l Level) Validate() error {
+	if l.Value.IsNegative() {
+		return errors.New("level should not be negative")
+	}
+
+	return nil
+}
+
+// Enabled ...
+func (l Level) Enabled() bool {
+	return l.Value.IsPositive()
+}
+
+// Offset отступ от цены входа
+func (l Level) Offset(entry decimal.Decimal) decimal.Decimal {
+	if l.Percent {
+		return entry.Mul(l.Value).Div(hundred)
+	}
+
+	return l.Value
+}
+
+func (l Level) String() string {
+	if !l.Enabled() {
+		return ""
+	}
+
+	s := l.Value.String()
+	if l.Percent {
+		s += "%"
+	}
+
+	return s
+}
@@ -0,0 +1,134 @@
+package robots
+
+import (
+	"authDB/internal/calendar"
+	"authDB/internal/schedule"
+	"errors"
+	"time"
+)
+
+// PlanAction что делает движок, когда FactYield достиг PlanYield
+type PlanAction string
+
+// Действия при достижении плановой доходности
+const (
+	// PlanNotify только уведомляет, робот продолжает торговать
+	PlanNotify PlanAction = "notify"
+	// PlanDeactivate деактивирует робота, открытая позиция остается
+	PlanDeactivate PlanAction = "deactivate"
+	// PlanClose закрывает позицию и завершает робота
+	PlanClose PlanAction = "close"
+)
+
+// ParsePlanAction пустое действие означает PlanNotify
+func ParsePlanAction(s string) (PlanAction, error) {
+	switch a := PlanAction(s); a {
+	case "":
+		return PlanNotify, nil
+	case PlanNotify, PlanDeactivate, PlanClose:
+		return a, nil
+	}
+
+	return "", errors.New("bad plan yield action")
+}
+
+// Outcome результат действия, который запоминается у робота
+func (a PlanAction) Outcome() string {
+	switch a {
+	case PlanDeactivate:
+		return "deactivated"
+	case PlanClose:
+		return "closed"
+	case PlanNotify:
+	}
+
+	return "notified"
+}
+
+// PlanActions все действия для формы создания
+func PlanActions() []PlanAction {
+	return []PlanAction{PlanNotify, PlanDeactivate, PlanClose}
+}
+
+// Sessions расписание торговых сессий робота, nil для разового окна PlanStart-PlanEnd
+func (r *Robot) Sessions() (*schedule.Schedule, error) {
+	if r.Schedule == "" {
+		return nil, nil
+	}
+
+	return schedule.Parse(r.Schedule, r.ScheduleExclude, time.UTC)
+}
+
+// InSession идет ли сейчас торговая сессия робота. Сессии действуют только внутри окна PlanStart-PlanEnd,
+// без расписания все окно считается одной сессией
+func (r *Robot) InSession(now time.Time) bool {
+	if !now.After(r.PlanStart.Time) || !now.Before(r.PlanEnd.Time) {
+		return false
+	}
+
+	s, err := r.Sessions()
+	if err != nil {
+		return false
+	}
+
+	return s == nil || s.Active(now)
+}
+
+// CheckSchedule проверяет расписание и приводит его к виду с явной зоной, loc зона расписания без зоны
+func CheckSchedule(rob *Robot, loc *time.Location) error {
+	if rob.Schedule == "" {
+		if len(rob.ScheduleExclude) > 0 {
+			return errors.New("exclude dates need a schedule")
+		}
+
+		return nil
+	}
+
+	s, err := schedule.Parse(rob.Schedule, rob.ScheduleExclude, loc)
+	if err != nil {
+		return err
+	}
+
+	rob.Schedule = s.String()
+
+	return nil
+}
+
+// NextSessionAfter начало следующей сессии после t внутри окна плана, nil если сессий больше не будет
+func (r *Robot) NextSessionAfter(t time.Time) *time.Time {
+	s, err := r.Sessions()
+	if err != nil {
+		return nil
+	}
+
+	next := r.PlanStart.Time
+
+	if s != nil {
+		from := t
+		if from.Before(r.PlanStart.Time) {
+			from = r.PlanStart.Time
+		}
+
+		var ok bool
+
+		if next, ok = s.Next(from); !ok {
+			return nil
+		}
+	}
+
+	if !next.After(t) || !next.Before(r.PlanEnd.Time) {
+		return nil
+	}
+
+	return &next
+}
+
+// CalendarWarning предупреждение, если за окно плана биржа тикера ни разу не торгует; пустое, если торги будут.
+// Это не ошибка: календарь может быть неполным, поэтому робота все равно можно сохранить
+func CalendarWarning(rob *Robot, c *calendar.Calendar) string {
+	if c.HasSession(rob.Ticker, rob.PlanStart.Time, rob.PlanEnd.Time) {
+		return ""
+	}
+
+	return "no trading sessions of " + c.Exchange(rob.Ticker).Name + " between plan start and plan end"
+}
@@ -1,12 +1,18 @@
 package robots
 
 import (
+	"authDB/internal/strategy"
 	"database/sql"
 	"errors"
 	"strconv"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// DefaultQuantity размер сделки, если он не указан при создании
+const DefaultQuantity = 1
+
 // Robot ...
 type Robot struct {
 	RobotID       int
@@ -15,17 +21,54 @@ type Robot struct {
 	IsFavorite    bool
 	IsActive      bool
 	Ticker        string
-	BuyPrice      float64
-	SellPrice     float64
+	BuyPrice      decimal.Decimal
+	SellPrice     decimal.Decimal
 	PlanStart     sql.NullTime
 	PlanEnd       sql.NullTime
-	PlanYield     float64
-	FactYield     float64
+	PlanYield     decimal.Decimal
+	FactYield     decimal.Decimal
 	DealsCount    int
 	ActivatedAt   sql.NullTime
 	DeactivatedAt sql.NullTime
 	CreatedAt     sql.NullTime
 	DeletedAt     sql.NullTime
+
+	// Quantity сколько бумаг покупается и продается в одной сделке
+	Quantity int
+	// StopLoss и TakeProfit уровни принудительного выхода из позиции ниже и выше цены входа
+	StopLoss   Level
+	TakeProfit Level
+	// TrailingStop отступ выхода от максимальной цены продажи после входа
+	TrailingStop Level
+	// Trail текущий трейлинг-стоп открытой позиции, заполняется только при чтении одного робота
+	Trail *Trail `json:",omitempty"`
+	// TradingHalted действует глобальная остановка торговли, заполняется при отдаче робота из API
+	TradingHalted bool
+	// MaxLoss и MaxDrawdown лимиты убытка и просадки от пика капитала с учетом открытой позиции, ноль выключает
+	MaxLoss     decimal.Decimal
+	MaxDrawdown decimal.Decimal
+
+	// OnPlanYield действие при достижении PlanYield, PlanOutcome и PlanReachedAt его результат
+	OnPlanYield   PlanAction
+	PlanOutcome   string
+	PlanReachedAt sql.NullTime
+
+	// Direction в какую сторону робот открывает позиции
+	Direction Direction
+
+	// Schedule повторяющиеся торговые сессии внутри окна плана, пустое означает разовое окно,
+	// ScheduleExclude даты без торговли. NextSession заполняется только при чтении одного робота
+	Schedule        string
+	ScheduleExclude []string
+	NextSession     *time.Time `json:",omitempty"`
+
+	Strategy       string
+	StrategyParams strategy.Params
+	IsDegraded     bool
+
+	Status          Status
+	StatusReason    string
+	StatusChangedAt sql.NullTime
 }
 
 // Robots ...
@@ -37,50 +80,55 @@ type Robots interface {
 	// GetAllRobots() ([]*Robot, error)
 	GetRobot(id int) (*Robot, error)
 	ActivateRobot(id int) error
-	DeactivateRobot(id int) error
+	DeactivateRobot(id int, reason string) error
+	// Update меняет параметры и запущенного робота тоже, sql.ErrNoRows если робот удален
 	Update(rob *Robot) error
 	FavoriteRobot(rob *Robot) error
 	FilterRobot(filter, how string) ([]*Robot, error)
 	UpdateActual(rob *Robot) error
 	GetAllNonDeletedRobots() ([]*Robot, error)
+	SetDegraded(id int, degraded bool) error
+	SetStatus(id int, to Status, reason string) error
+	// SetPlanReached запоминает результат действия по плановой доходности
+	SetPlanReached(id int, outcome string) error
 }
 
-// FormInformationForCreate ...
-func FormInformationForCreate(buy, sell, yield, planStart, planEnd string) (Robot, error) { //nolint
+// FormInformationForCreate времена плана без зоны считаются временем пользователя loc
+func FormInformationForCreate(buy, sell, yield, planStart, planEnd, quantity string, loc *time.Location) (Robot, error) { //nolint
 	var (
 		rob Robot
 		err error
 	)
 
-	rob.BuyPrice, err = strconv.ParseFloat(buy, 64)
+	rob.BuyPrice, err = decimal.NewFromString(buy)
 	if err != nil || buy == "" {
 		err = errors.New("bad buy price")
 
 		return rob, err
 	}
 
-	rob.SellPrice, err = strconv.ParseFloat(sell, 64)
+	rob.SellPrice, err = decimal.NewFromString(sell)
 	if err != nil || sell == "" {
 		err = errors.New("bad sell price")
 
 		return Robot{}, err
 	}
 
-	rob.PlanStart.Time, err = time.Parse(time.RFC3339, planStart)
+	rob.PlanStart.Time, err = ParsePlanTime(planStart, loc)
 	if err != nil || planStart == "" {
 		err = errors.New("bad plan start")
 
 		return Robot{}, err
 	}
 
-	rob.PlanEnd.Time, err = time.Parse(time.RFC3339, planEnd)
+	rob.PlanEnd.Time, err = ParsePlanTime(planEnd, loc)
 	if err != nil || planEnd == "" {
 		err = errors.New("bad plan end")
 
 		return Robot{}, err
 	}
 
-	rob.PlanYield, err = strconv.ParseFloat(yield, 64)
+	rob.PlanYield, err = decimal.NewFromString(yield)
 	if err != nil || yield == "" {
 		err = errors.New("bad plan yield")
 
@@ -93,6 +141,17 @@ func FormInformationForCreate(buy, sell, yield, planStart, planEnd string) (Robo
 		return Robot{}, err
 	}
 
+	rob.Quantity = DefaultQuantity
+
+	if quantity != "" {
+		rob.Quantity, err = strconv.Atoi(quantity)
+		if err != nil || rob.Quantity <= 0 {
+			err = errors.New("bad quantity")
+
+			return Robot{}, err
+		}
+	}
+
 	return rob, nil
 }
 
@@ -104,13 +163,13 @@ func ChackRobotForUpdate(rob Robot) error {
 		return err
 	}
 
-	if rob.BuyPrice == 0 {
+	if rob.BuyPrice.IsZero() {
 		err := errors.New("bad buy price")
 
 		return err
 	}
 
-	if rob.SellPrice == 0 {
+	if rob.SellPrice.IsZero() {
 		err := errors.New("bad sell price")
 
 		return err
@@ -128,7 +187,7 @@ func ChackRobotForUpdate(rob Robot) error {
 		return err
 	}
 
-	if rob.PlanYield == 0 {
+	if rob.PlanYield.IsZero() {
 		err := errors.New("bad plan yield")
 
 		return err
@@ -140,5 +199,113 @@ func ChackRobotForUpdate(rob Robot) error {
 		return err
 	}
 
+	if rob.Quantity <= 0 {
+		err := errors.New("bad quantity")
+
+		return err
+	}
+
+	if err := CheckExits(rob); err != nil {
+		return err
+	}
+
+	if _, err := ParsePlanAction(string(rob.OnPlanYield)); err != nil {
+		return err
+	}
+
+	// пороги против направления проверяет DirectionChanged, здесь только само направление
+	if _, err := ParseDirection(string(rob.Direction)); err != nil {
+		return err
+	}
+
+	if err := CheckSchedule(&rob, time.UTC); err != nil {
+		return err
+	}
+
+	if rob.MaxLoss.IsNegative() {
+		return errors.New("bad max loss")
+	}
+
+	if rob.MaxDrawdown.IsNegative() {
+		return errors.New("bad max drawdown")
+	}
+
+	if err := strategy.Validate(rob.Strategy, rob.StrategyParams); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// CheckExits проверяет уровни стоп-лосса, тейк-профита и трейлинг-стопа
+func CheckExits(rob Robot) error {
+	if err := rob.StopLoss.Validate(); err != nil {
+		return errors.New("bad stop loss")
+	}
+
+	if rob.StopLoss.Percent && rob.StopLoss.Value.GreaterThanOrEqual(hundred) {
+		return errors.New("stop loss percent should be less than 100")
+	}
+
+	if err := rob.TakeProfit.Validate(); err != nil {
+		return errors.New("bad take profit")
+	}
+
+	if err := rob.TrailingStop.Validate(); err != nil {
+		return errors.New("bad trailing stop")
+	}
+
+	if rob.TrailingStop.Percent && rob.TrailingStop.Value.GreaterThanOrEqual(hundred) {
+		return errors.New("trailing stop percent should be less than 100")
+	}
+
+	return nil
+}
+
+// ParseLimit разбирает неотрицательный денежный лимит, пустая строка выключает лимит
+func ParseLimit(s string) (decimal.Decimal, error) {
+	if s == "" {
+		return decimal.Zero, nil
+	}
+
+	v, err := decimal.NewFromString(s)
+	if err != nil || v.IsNegative() {
+		return decimal.Zero, errors.New("bad limit " + s)
+	}
+
+	return v, nil
+}
+
+// layouts времени плана без зоны, которые принимает форма
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// ParsePlanTime разбирает RFC3339 или время без зоны в зоне пользователя и возвращает его в UTC
+func ParsePlanTime(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t.UTC(), nil
+	}
+
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// In переводит времена робота в зону пользователя для отдачи из API
+func (r *Robot) In(loc *time.Location) {
+	for _, t := range []*sql.NullTime{&r.PlanStart, &r.PlanEnd, &r.ActivatedAt, &r.DeactivatedAt, &r.CreatedAt,
+		&r.DeletedAt, &r.StatusChangedAt, &r.PlanReachedAt} {
+		if t.Valid {
+			t.Time = t.Time.In(loc)
+		}
+	}
+
+	if r.NextSession != nil {
+		next := r.NextSession.In(loc)
+		r.NextSession = &next
+	}
+}
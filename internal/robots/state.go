@@ -0,0 +1,70 @@
+package robots
+
+import (
+	"authDB/internal/strategy"
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// State торговое состояние робота, которое переживает рестарт процесса
+type State struct {
+	RobotID    int
+	Side       string
+	EntryPrice decimal.Decimal
+	Quantity   int
+	// HighWater лучшая для позиции цена с момента входа, от нее считается трейлинг-стоп
+	HighWater decimal.Decimal
+	// PeakEquity максимум капитала робота, от него считается просадка
+	PeakEquity decimal.Decimal
+	EntryTime  sql.NullTime
+	LastTickTs sql.NullTime
+	// OrderSeq номер следующей заявки робота, растет вместе с записью сделки
+	OrderSeq int
+	// Pending заявка, отправленная на биржу, но еще не записанная сделкой
+	Pending   *PendingOrder
+	UpdatedAt time.Time
+}
+
+// PendingOrder заявка робота, которая хранится до записи сделки. После сбоя записи или рестарта
+// воркер повторяет ее с тем же ClientOrderID, и биржа возвращает уже случившееся исполнение
+type PendingOrder struct {
+	ClientOrderID string
+	Side          string
+	Quantity      int
+	LimitPrice    decimal.Decimal
+	Reason        string
+	// Open заявка открывает позицию Position, иначе закрывает ее
+	Open     bool
+	Position string
+}
+
+// States хранилище торговых состояний роботов
+type States interface {
+	SaveState(st *State) error
+	// GetState возвращает nil без ошибки, если состояние еще не сохранялось
+	GetState(robotID int) (*State, error)
+}
+
+// Trail где сейчас стоит трейлинг-стоп открытой позиции
+type Trail struct {
+	HighWater decimal.Decimal
+	ExitPrice decimal.Decimal
+}
+
+// TrailFor считает трейлинг-стоп робота по сохраненному состоянию, nil если стопа нет
+func TrailFor(rob *Robot, st *State) *Trail {
+	if st == nil || !rob.TrailingStop.Enabled() || st.HighWater.IsZero() {
+		return nil
+	}
+
+	offset := rob.TrailingStop.Offset(st.HighWater)
+
+	// short откупается, когда цена отскочила вверх от минимума
+	if strategy.ParseSide(st.Side) == strategy.Short {
+		return &Trail{HighWater: st.HighWater, ExitPrice: st.HighWater.Add(offset)}
+	}
+
+	return &Trail{HighWater: st.HighWater, ExitPrice: st.HighWater.Sub(offset)}
+}
@@ -0,0 +1,88 @@
+package robots
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Status стадия жизненного цикла робота
+type Status string
+
+// Стадии жизненного цикла робота
+const (
+	// StatusDraft робот создан или деактивирован и не торгует
+	StatusDraft Status = "draft"
+	// StatusScheduled робот активирован и ждет начала планового окна
+	StatusScheduled Status = "scheduled"
+	// StatusRunning воркер робота торгует
+	StatusRunning Status = "running"
+	// StatusPaused робот приостановлен пользователем, позиция сохранена
+	StatusPaused Status = "paused"
+	// StatusFinished плановое окно закончилось
+	StatusFinished Status = "finished"
+	// StatusFailed воркер робота остановился с ошибкой
+	StatusFailed Status = "failed"
+	// StatusDeleted робот удален
+	StatusDeleted Status = "deleted"
+)
+
+// ErrBadTransition переход между стадиями запрещен
+var ErrBadTransition = errors.New("bad robot status transition")
+
+// transitions таблица разрешенных переходов: из какой стадии в какие можно перейти
+//
+//	draft     -> scheduled, deleted
+//	scheduled -> running, draft, finished, failed, deleted
+//	running   -> paused, scheduled, finished, failed, draft, deleted
+//	paused    -> running, finished, failed, draft, deleted
+//	finished  -> scheduled, draft, deleted
+//	failed    -> scheduled, draft, deleted
+//	deleted   -> нет переходов
+var transitions = map[Status][]Status{
+	StatusDraft:     {StatusScheduled, StatusDeleted},
+	StatusScheduled: {StatusRunning, StatusDraft, StatusFinished, StatusFailed, StatusDeleted},
+	StatusRunning:   {StatusPaused, StatusScheduled, StatusFinished, StatusFailed, StatusDraft, StatusDeleted},
+	StatusPaused:    {StatusRunning, StatusFinished, StatusFailed, StatusDraft, StatusDeleted},
+	StatusFinished:  {StatusScheduled, StatusDraft, StatusDeleted},
+	StatusFailed:    {StatusScheduled, StatusDraft, StatusDeleted},
+	StatusDeleted:   {},
+}
+
+// CanTransition проверяет, разрешен ли переход
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckTransition возвращает ErrBadTransition с пояснением, если переход запрещен
+func CheckTransition(from, to Status) error {
+	if !CanTransition(from, to) {
+		return errors.Wrapf(ErrBadTransition, "can't move robot from %s to %s", from, to)
+	}
+
+	return nil
+}
+
+// From стадии, из которых можно перейти в to
+func From(to Status) []string {
+	var from []string
+
+	for s, next := range transitions {
+		for _, n := range next {
+			if n == to {
+				from = append(from, string(s))
+			}
+		}
+	}
+
+	return from
+}
+
+// IsLive робот активирован: ждет окна, торгует или на паузе
+func (s Status) IsLive() bool {
+	return s == StatusScheduled || s == StatusRunning || s == StatusPaused
+}
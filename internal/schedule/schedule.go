@@ -0,0 +1,305 @@
+package schedule
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DateLayout формат дат исключений
+const DateLayout = "2006-01-02"
+
+// maxDuration самая длинная сессия, дальше назад начало сессии не ищется
+const maxDuration = 7 * 24 * time.Hour
+
+// Schedule повторяющиеся торговые сессии робота.
+// Записывается либо днями и часами: "weekdays 10:00-18:45 Europe/Moscow", "mon,wed,fri 09:30-16:00", "daily 22:00-02:00",
+// либо как cron начала сессии и ее длительность: "0 10 * * 1-5 8h45m Europe/Moscow".
+// Зона необязательна, без нее берется зона по умолчанию из Parse
+type Schedule struct {
+	minutes, hours, doms, months, dows field
+	// domStar и dowStar день месяца и день недели не ограничены, как в cron
+	domStar, dowStar bool
+	duration         time.Duration
+	loc              *time.Location
+	exclude          map[string]bool
+	spec             string
+}
+
+type field map[int]bool
+
+var dayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+var dayAliases = map[string]string{"daily": "sun-sat", "weekdays": "mon-fri", "weekends": "sat,sun"}
+
+// Parse разбирает расписание и даты исключений в формате DateLayout, loc зона расписания без явной зоны
+func Parse(spec string, exclude []string, loc *time.Location) (*Schedule, error) {
+	tokens := strings.Fields(spec)
+	if len(tokens) == 0 {
+		return nil, errors.New("empty schedule")
+	}
+
+	s := &Schedule{loc: loc, exclude: make(map[string]bool)}
+
+	if last := tokens[len(tokens)-1]; len(tokens) > 2 && last != "Local" {
+		if z, err := time.LoadLocation(last); err == nil {
+			s.loc = z
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
+
+	var err error
+
+	switch len(tokens) {
+	case 2:
+		err = s.parseDays(tokens[0], tokens[1])
+	case 6:
+		err = s.parseCron(tokens)
+	default:
+		err = errors.New("schedule should be \"days HH:MM-HH:MM [zone]\" or \"min hour dom month dow duration [zone]\"")
+	}
+
+	if err != nil {
+		return nil, errors.WithMessage(err, "bad schedule "+strconv.Quote(spec))
+	}
+
+	for _, d := range exclude {
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return nil, errors.New("bad exclude date " + d)
+		}
+
+		s.exclude[d] = true
+	}
+
+	s.spec = strings.Join(append(tokens, s.loc.String()), " ")
+
+	return s, nil
+}
+
+func (s *Schedule) parseDays(days, hours string) error {
+	days = strings.ToLower(days)
+
+	if alias, ok := dayAliases[days]; ok {
+		days = alias
+	}
+
+	dows, err := parseField(days, 0, 6, dayNames)
+	if err != nil {
+		return errors.WithMessage(err, "bad days")
+	}
+
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return errors.New("bad hours " + hours)
+	}
+
+	from, err := time.Parse("15:04", parts[0])
+	if err != nil {
+		return errors.New("bad session start " + parts[0])
+	}
+
+	to, err := time.Parse("15:04", parts[1])
+	if err != nil {
+		return errors.New("bad session end " + parts[1])
+	}
+
+	s.duration = to.Sub(from)
+	if s.duration <= 0 {
+		// сессия через полночь
+		s.duration += 24 * time.Hour
+	}
+
+	s.minutes = field{from.Minute(): true}
+	s.hours = field{from.Hour(): true}
+	s.doms, s.domStar = rangeField(1, 31), true
+	s.months = rangeField(1, 12)
+	s.dows, s.dowStar = dows, days == "sun-sat"
+
+	return nil
+}
+
+func (s *Schedule) parseCron(tokens []string) error {
+	var err error
+
+	if s.minutes, err = parseField(tokens[0], 0, 59, nil); err != nil {
+		return errors.WithMessage(err, "bad minutes")
+	}
+
+	if s.hours, err = parseField(tokens[1], 0, 23, nil); err != nil {
+		return errors.WithMessage(err, "bad hours")
+	}
+
+	if s.doms, err = parseField(tokens[2], 1, 31, nil); err != nil {
+		return errors.WithMessage(err, "bad days of month")
+	}
+
+	if s.months, err = parseField(tokens[3], 1, 12, nil); err != nil {
+		return errors.WithMessage(err, "bad months")
+	}
+
+	// воскресенье в cron и 0, и 7
+	if s.dows, err = parseField(strings.ToLower(tokens[4]), 0, 7, dayNames); err != nil {
+		return errors.WithMessage(err, "bad days of week")
+	}
+
+	if s.dows[7] {
+		s.dows[0] = true
+	}
+
+	s.domStar, s.dowStar = tokens[2] == "*", tokens[4] == "*"
+
+	if s.duration, err = time.ParseDuration(tokens[5]); err != nil || s.duration <= 0 || s.duration > maxDuration {
+		return errors.New("bad session duration " + tokens[5])
+	}
+
+	return nil
+}
+
+// parseField разбирает поле cron: "*", "5", "1-5", "mon-fri", "*/15", "1,3,5"
+func parseField(s string, min, max int, names map[string]int) (field, error) {
+	f := make(field)
+
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return nil, errors.New("bad step " + part)
+			}
+
+			step, part = n, part[:i]
+		}
+
+		lo, hi := min, max
+
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+
+			if lo, err = value(bounds[0], names); err != nil {
+				return nil, err
+			}
+
+			hi = lo
+
+			if len(bounds) == 2 {
+				if hi, err = value(bounds[1], names); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, errors.New("out of range " + part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			f[v] = true
+		}
+	}
+
+	return f, nil
+}
+
+func value(s string, names map[string]int) (int, error) {
+	if v, ok := names[s]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.New("bad value " + s)
+	}
+
+	return v, nil
+}
+
+func rangeField(min, max int) field {
+	f := make(field)
+	for v := min; v <= max; v++ {
+		f[v] = true
+	}
+
+	return f
+}
+
+// Active идет ли сейчас сессия: она началась не раньше duration назад и ее дата не исключена
+func (s *Schedule) Active(now time.Time) bool {
+	now = now.In(s.loc)
+
+	for day := midnight(now); !day.Before(midnight(now.Add(-s.duration))); day = day.AddDate(0, 0, -1) {
+		for _, start := range s.starts(day) {
+			if !start.After(now) && now.Before(start.Add(s.duration)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Next начало ближайшей сессии после t в пределах года
+func (s *Schedule) Next(t time.Time) (time.Time, bool) {
+	t = t.In(s.loc)
+
+	for day := midnight(t); day.Before(t.AddDate(1, 0, 0)); day = day.AddDate(0, 0, 1) {
+		for _, start := range s.starts(day) {
+			if start.After(t) {
+				return start, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// starts начала сессий в день day по возрастанию, пусто в исключенные даты
+func (s *Schedule) starts(day time.Time) []time.Time {
+	if s.exclude[day.Format(DateLayout)] || !s.matchDay(day) {
+		return nil
+	}
+
+	var starts []time.Time
+
+	for h := range s.hours {
+		for m := range s.minutes {
+			starts = append(starts, time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, s.loc))
+		}
+	}
+
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	return starts
+}
+
+// matchDay день подходит по месяцу, дню месяца и дню недели; если ограничены оба дня, хватает одного, как в cron
+func (s *Schedule) matchDay(day time.Time) bool {
+	if !s.months[int(day.Month())] {
+		return false
+	}
+
+	dom, dow := s.doms[day.Day()], s.dows[int(day.Weekday())]
+
+	switch {
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// String расписание с явной зоной
+func (s *Schedule) String() string {
+	return s.spec
+}
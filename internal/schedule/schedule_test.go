@@ -0,0 +1,171 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func zone(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loc
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		spec    string
+		exclude []string
+	}{
+		{spec: ""},
+		{spec: "weekdays"},
+		{spec: "weekdays 10:00"},
+		{spec: "weekdays 10:00-25:00"},
+		{spec: "mon-xyz 10:00-11:00"},
+		{spec: "fri-mon 10:00-11:00"},
+		{spec: "0 10 * * 1-5"},
+		{spec: "0 10 * * 1-5 0h"},
+		{spec: "0 10 * * 1-5 200h"},
+		{spec: "60 10 * * 1-5 1h"},
+		{spec: "0 10 * * */0 1h"},
+		{spec: "daily 10:00-11:00", exclude: []string{"19.10.2026"}},
+	}
+
+	for _, c := range cases {
+		if _, err := Parse(c.spec, c.exclude, time.UTC); err == nil {
+			t.Errorf("Parse(%q, %v) = nil error", c.spec, c.exclude)
+		}
+	}
+}
+
+func TestParseDayNamesIgnoreCase(t *testing.T) {
+	for _, spec := range []string{"sun-sat 10:00-11:00", "SUN-SAT 10:00-11:00", "Sun-Sat 10:00-11:00", "DAILY 10:00-11:00"} {
+		s, err := Parse(spec, nil, time.UTC)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", spec, err)
+		}
+
+		if !s.dowStar || len(s.dows) != 7 {
+			t.Errorf("Parse(%q) dowStar %v with %d days, want every day unrestricted", spec, s.dowStar, len(s.dows))
+		}
+	}
+
+	s, err := Parse("0 10 * * MON-FRI 1h", nil, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.dows) != 5 || s.dows[0] || s.dows[6] {
+		t.Errorf("cron MON-FRI days = %v, want monday to friday", s.dows)
+	}
+}
+
+func TestActive(t *testing.T) {
+	msk := zone(t, "Europe/Moscow")
+
+	// 2026-10-16 пятница, 17 и 18 выходные, 19 понедельник
+	cases := []struct {
+		name    string
+		spec    string
+		exclude []string
+		at      time.Time
+		want    bool
+	}{
+		{"weekday session", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 19, 12, 0, 0, 0, msk), true},
+		{"session start", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 19, 10, 0, 0, 0, msk), true},
+		{"before start", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 19, 9, 59, 0, 0, msk), false},
+		{"end is exclusive", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 19, 18, 45, 0, 0, msk), false},
+		{"weekend", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 17, 12, 0, 0, 0, msk), false},
+		{"zone of the spec", "weekdays 10:00-18:45 Europe/Moscow", nil, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), true},
+		{"listed days", "mon,wed,fri 09:30-16:00", nil, time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC), true},
+		{"unlisted day", "mon,wed,fri 09:30-16:00", nil, time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC), false},
+		{"overnight before midnight", "daily 22:00-02:00", nil, time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC), true},
+		{"overnight after midnight", "daily 22:00-02:00", nil, time.Date(2026, 10, 20, 1, 30, 0, 0, time.UTC), true},
+		{"overnight end", "daily 22:00-02:00", nil, time.Date(2026, 10, 20, 2, 0, 0, 0, time.UTC), false},
+		{"overnight gap", "daily 22:00-02:00", nil, time.Date(2026, 10, 20, 21, 59, 0, 0, time.UTC), false},
+		{"overnight into saturday", "fri 22:00-02:00", nil, time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC), true},
+		{"overnight saturday night", "fri 22:00-02:00", nil, time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC), false},
+		{"excluded date", "daily 10:00-12:00", []string{"2026-10-19"}, time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC), false},
+		{"day after excluded", "daily 10:00-12:00", []string{"2026-10-19"}, time.Date(2026, 10, 20, 11, 0, 0, 0, time.UTC), true},
+		{"overnight from excluded date", "daily 22:00-02:00", []string{"2026-10-19"}, time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC), false},
+		{"cron weekdays", "0 10 * * 1-5 8h45m Europe/Moscow", nil, time.Date(2026, 10, 19, 18, 0, 0, 0, msk), true},
+		{"cron weekend", "0 10 * * 1-5 8h45m Europe/Moscow", nil, time.Date(2026, 10, 18, 12, 0, 0, 0, msk), false},
+		{"cron sunday as 7", "0 10 * * 7 1h", nil, time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC), true},
+		{"cron every 15 minutes", "*/15 * * * * 5m", nil, time.Date(2026, 10, 19, 13, 47, 0, 0, time.UTC), true},
+		{"cron between steps", "*/15 * * * * 5m", nil, time.Date(2026, 10, 19, 13, 51, 0, 0, time.UTC), false},
+		{"cron day of month", "0 10 1 * * 1h", nil, time.Date(2026, 10, 1, 10, 30, 0, 0, time.UTC), true},
+		{"cron other day of month", "0 10 1 * * 1h", nil, time.Date(2026, 10, 2, 10, 30, 0, 0, time.UTC), false},
+		{"cron day of month or week", "0 10 1 * mon 1h", nil, time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), true},
+		{"cron neither day", "0 10 1 * mon 1h", nil, time.Date(2026, 10, 20, 10, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.spec, c.exclude, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+
+		if got := s.Active(c.at); got != c.want {
+			t.Errorf("%s: Active(%s) = %v, want %v", c.name, c.at, got, c.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	msk := zone(t, "Europe/Moscow")
+	saturday := time.Date(2026, 10, 17, 12, 0, 0, 0, msk)
+
+	cases := []struct {
+		name    string
+		spec    string
+		exclude []string
+		after   time.Time
+		want    time.Time
+		ok      bool
+	}{
+		{"over the weekend", "weekdays 10:00-18:45 Europe/Moscow", nil, saturday,
+			time.Date(2026, 10, 19, 10, 0, 0, 0, msk), true},
+		{"skips excluded date", "weekdays 10:00-18:45 Europe/Moscow", []string{"2026-10-19"}, saturday,
+			time.Date(2026, 10, 20, 10, 0, 0, 0, msk), true},
+		{"later today", "daily 22:00-02:00", nil, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC), true},
+		{"strictly after", "daily 22:00-02:00", nil, time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+			time.Date(2026, 10, 20, 22, 0, 0, 0, time.UTC), true},
+		{"never", "0 10 30 2 * 1h", nil, saturday, time.Time{}, false},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.spec, c.exclude, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+
+		got, ok := s.Next(c.after)
+		if ok != c.ok || !got.Equal(c.want) {
+			t.Errorf("%s: Next(%s) = %s %v, want %s %v", c.name, c.after, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := map[string]string{
+		"weekdays 10:00-18:45":               "weekdays 10:00-18:45 UTC",
+		"weekdays 10:00-18:45 Europe/Moscow": "weekdays 10:00-18:45 Europe/Moscow",
+		"0 10 * * 1-5 8h45m":                 "0 10 * * 1-5 8h45m UTC",
+	}
+
+	for spec, want := range cases {
+		s, err := Parse(spec, nil, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if s.String() != want {
+			t.Errorf("String() of %q = %q, want %q", spec, s.String(), want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package sessions
 
 import (
+	"authDB/internal/clock"
 	"authDB/internal/user"
 	"encoding/base64"
 	"fmt"
@@ -55,22 +56,20 @@ func DecodeToken(token string) (int, error) {
 	return userID, nil
 }
 
-const (
-	min  = 30
-	hour = 3
-)
+const min = 30
 
 //CreateSes создают сессию
 func CreateSes(user *user.User) (string, *Session) {
 	var ses Session
 
 	token := CreateToken(user.ID, user.Email, user.Password)
+	now := clock.Now()
 
 	ses = Session{
 		SessionID:  token,
 		UserID:     user.ID,
-		CreatedAt:  time.Now(),
-		ValidUntil: time.Now().Add(min * time.Minute),
+		CreatedAt:  now,
+		ValidUntil: now.Add(min * time.Minute),
 	}
 
 	return token, &ses
@@ -78,7 +77,7 @@ func CreateSes(user *user.User) (string, *Session) {
 
 //CheckValidSes ...
 func CheckValidSes(userToken string, s *Session) bool {
-	if s.SessionID == userToken && time.Now().Add(hour*time.Hour).Before(s.ValidUntil) { //3 часа, пушто временная зона, да - это костыль
+	if s.SessionID == userToken && clock.Now().Before(s.ValidUntil) {
 		return true
 	}
 
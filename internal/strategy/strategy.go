@@ -0,0 +1,177 @@
+package strategy
+
+import (
+	"authDB/internal/fintech"
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Default стратегия, которая используется, если робот не указал свою
+const Default = "threshold"
+
+// Decision решение стратегии по очередной котировке
+type Decision int
+
+// Возможные решения стратегии
+const (
+	Hold Decision = iota
+	Buy
+	Sell
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Buy:
+		return "buy"
+	case Sell:
+		return "sell"
+	default:
+		return "hold"
+	}
+}
+
+// Side текущая позиция робота
+type Side int
+
+// Возможные позиции робота
+const (
+	Flat Side = iota
+	Long
+	Short
+)
+
+func (s Side) String() string {
+	switch s {
+	case Long:
+		return "long"
+	case Short:
+		return "short"
+	default:
+		return "flat"
+	}
+}
+
+// ParseSide обратное к Side.String, неизвестное значение считается Flat
+func ParseSide(s string) Side {
+	switch s {
+	case "long":
+		return Long
+	case "short":
+		return Short
+	}
+
+	return Flat
+}
+
+// Strategy принимает решение по каждой котировке с учетом текущей позиции
+type Strategy interface {
+	Decide(side Side, price *fintech.PriceResponse) Decision
+}
+
+// Params параметры стратегии, хранятся в jsonb
+type Params map[string]float64
+
+// Value ...
+func (p Params) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(p)
+}
+
+// Scan ...
+func (p *Params) Scan(src interface{}) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		*p = Params{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.Errorf("can't scan strategy params from %T", src)
+	}
+
+	return json.Unmarshal(data, p)
+}
+
+// Config настройки робота, нужные стратегии
+type Config struct {
+	BuyPrice  decimal.Decimal
+	SellPrice decimal.Decimal
+	Params    Params
+}
+
+// Factory создает стратегию по настройкам робота
+type Factory func(cfg Config) (Strategy, error)
+
+var registry = map[string]Factory{
+	Default: newThreshold,
+}
+
+// New создает стратегию по имени
+func New(name string, cfg Config) (Strategy, error) {
+	if name == "" {
+		name = Default
+	}
+
+	factory, ok := registry[name]
+	if !ok {
+		return nil, errors.Errorf("unknown strategy %q", name)
+	}
+
+	s, err := factory(cfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "bad params for strategy "+name)
+	}
+
+	return s, nil
+}
+
+// Names список зарегистрированных стратегий
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Parse разбирает имя и параметры стратегии из формы
+func Parse(name, params string) (string, Params, error) {
+	p := Params{}
+
+	if name == "" {
+		name = Default
+	}
+
+	if params != "" {
+		if err := json.Unmarshal([]byte(params), &p); err != nil {
+			return "", nil, errors.New("bad strategy params")
+		}
+	}
+
+	if err := Validate(name, p); err != nil {
+		return "", nil, err
+	}
+
+	return name, p, nil
+}
+
+// Validate проверяет, что стратегия существует и принимает такие параметры
+func Validate(name string, params Params) error {
+	_, err := New(name, Config{Params: params})
+
+	return err
+}
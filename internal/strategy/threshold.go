@@ -0,0 +1,41 @@
+package strategy
+
+import (
+	"authDB/internal/fintech"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// threshold покупает, когда цена опустилась до BuyPrice, и продает, когда выросла до SellPrice.
+// Без позиции предлагает и то и другое, направление выбирает трейдер
+type threshold struct {
+	buyPrice  decimal.Decimal
+	sellPrice decimal.Decimal
+}
+
+func newThreshold(cfg Config) (Strategy, error) {
+	for key := range cfg.Params {
+		return nil, errors.Errorf("unknown param %q", key)
+	}
+
+	return &threshold{
+		buyPrice:  cfg.BuyPrice,
+		sellPrice: cfg.SellPrice,
+	}, nil
+}
+
+// Decide ...
+func (t *threshold) Decide(side Side, price *fintech.PriceResponse) Decision {
+	buy := t.buyPrice.GreaterThanOrEqual(decimal.NewFromFloat(price.BuyPrice))
+	sell := t.sellPrice.LessThanOrEqual(decimal.NewFromFloat(price.SellPrice))
+
+	switch {
+	case buy && side != Long:
+		return Buy
+	case sell && side != Short:
+		return Sell
+	}
+
+	return Hold
+}
@@ -0,0 +1,359 @@
+package trading
+
+import (
+	"authDB/internal/deals"
+	"authDB/internal/fintech"
+	"authDB/internal/robots"
+	"authDB/internal/strategy"
+	"database/sql"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Fill сделка трейдера: заявка по сигналу стратегии или ее исполнение
+type Fill struct {
+	Side     string
+	Price    decimal.Decimal
+	Quantity int
+	Reason   string
+	// Open сделка открывает позицию Position, иначе закрывает ее
+	Open     bool
+	Position strategy.Side
+}
+
+// Order заявка на бирже по сделке трейдера
+func (f *Fill) Order(clientOrderID string) *robots.PendingOrder {
+	return &robots.PendingOrder{
+		ClientOrderID: clientOrderID,
+		Side:          f.Side,
+		Quantity:      f.Quantity,
+		LimitPrice:    f.Price,
+		Reason:        f.Reason,
+		Open:          f.Open,
+		Position:      f.Position.String(),
+	}
+}
+
+// FillFor сделка трейдера по сохраненной заявке
+func FillFor(o *robots.PendingOrder) *Fill {
+	return &Fill{
+		Side:     o.Side,
+		Price:    o.LimitPrice,
+		Quantity: o.Quantity,
+		Reason:   o.Reason,
+		Open:     o.Open,
+		Position: strategy.ParseSide(o.Position),
+	}
+}
+
+// Amount сумма сделки
+func (f *Fill) Amount() decimal.Decimal {
+	return f.Price.Mul(decimal.NewFromInt(int64(f.Quantity)))
+}
+
+// Trader торговая логика робота без хранилищ и сети: стратегия, позиция и счетчики.
+// Ее используют и живой воркер, и бэктест, поэтому результаты у них совпадают
+type Trader struct {
+	strat      strategy.Strategy
+	quantity   int
+	stopLoss   robots.Level
+	takeProfit robots.Level
+	trailing   robots.Level
+	maxLoss    decimal.Decimal
+	maxDD      decimal.Decimal
+	direction  robots.Direction
+	planYield  decimal.Decimal
+	onPlan     robots.PlanAction
+	planDone   bool
+
+	Side       strategy.Side
+	EntryPrice decimal.Decimal
+	// Position сколько бумаг в открытой позиции
+	Position int
+	// HighWater лучшая для позиции цена с момента входа: максимум продажи для long
+	// и минимум покупки для short
+	HighWater decimal.Decimal
+	// PeakEquity максимум капитала: реализованный результат плюс открытая позиция
+	PeakEquity decimal.Decimal
+	EntryTime  time.Time
+	LastTickTs time.Time
+	DealsCount int
+	FactYield  decimal.Decimal
+	// OrderSeq и Pending см. robots.State
+	OrderSeq int
+	Pending  *robots.PendingOrder
+}
+
+// New создает трейдера по параметрам робота
+func New(rob *robots.Robot) (*Trader, error) {
+	t := &Trader{
+		Side:       strategy.Flat,
+		DealsCount: rob.DealsCount,
+		FactYield:  rob.FactYield,
+		PeakEquity: decimal.Max(rob.FactYield, decimal.Zero),
+	}
+
+	if err := t.Configure(rob); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Configure применяет новые параметры робота, сохраняя позицию и счетчики
+func (t *Trader) Configure(rob *robots.Robot) error {
+	strat, err := strategy.New(rob.Strategy, strategy.Config{
+		BuyPrice:  rob.BuyPrice,
+		SellPrice: rob.SellPrice,
+		Params:    rob.StrategyParams,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "bad strategy")
+	}
+
+	t.strat = strat
+	t.quantity = rob.Quantity
+	t.stopLoss = rob.StopLoss
+	t.takeProfit = rob.TakeProfit
+	t.trailing = rob.TrailingStop
+	t.maxLoss = rob.MaxLoss
+	t.maxDD = rob.MaxDrawdown
+	t.direction = rob.Direction
+	t.planYield = rob.PlanYield
+	t.onPlan = rob.OnPlanYield
+	t.planDone = rob.PlanReachedAt.Valid
+
+	if t.quantity <= 0 {
+		t.quantity = robots.DefaultQuantity
+	}
+
+	return nil
+}
+
+// Restore поднимает сохраненную позицию
+func (t *Trader) Restore(st *robots.State) {
+	t.Side = strategy.ParseSide(st.Side)
+	t.EntryPrice = st.EntryPrice
+	t.Position = st.Quantity
+	t.HighWater = st.HighWater
+	t.PeakEquity = decimal.Max(t.PeakEquity, st.PeakEquity)
+	t.EntryTime = st.EntryTime.Time
+	t.LastTickTs = st.LastTickTs.Time
+	t.OrderSeq = st.OrderSeq
+	t.Pending = st.Pending
+}
+
+// State снимок позиции для сохранения
+func (t *Trader) State(robotID int) *robots.State {
+	return &robots.State{
+		RobotID:    robotID,
+		Side:       t.Side.String(),
+		EntryPrice: t.EntryPrice,
+		Quantity:   t.Position,
+		HighWater:  t.HighWater,
+		PeakEquity: t.PeakEquity,
+		EntryTime:  sql.NullTime{Time: t.EntryTime, Valid: !t.EntryTime.IsZero()},
+		LastTickTs: sql.NullTime{Time: t.LastTickTs, Valid: !t.LastTickTs.IsZero()},
+		OrderSeq:   t.OrderSeq,
+		Pending:    t.Pending,
+	}
+}
+
+// Signal возвращает заявку, которую стратегия хочет выставить по котировке, позицию не меняет.
+// Котировки не новее уже обработанной пропускаются
+func (t *Trader) Signal(data *fintech.PriceResponse) *Fill {
+	ts := QuoteTime(data)
+	if !ts.IsZero() && !ts.After(t.LastTickTs) {
+		return nil
+	}
+
+	t.LastTickTs = ts
+
+	if reason := t.exit(data); reason != "" {
+		return t.Close(data, reason)
+	}
+
+	switch d := t.strat.Decide(t.Side, data); {
+	case t.Side == strategy.Long && d == strategy.Sell, t.Side == strategy.Short && d == strategy.Buy:
+		return t.Close(data, deals.ExitSignal)
+	case t.Side != strategy.Flat:
+	case d == strategy.Buy && t.direction.Allows(strategy.Long):
+		return &Fill{Side: deals.Buy, Price: BuyPrice(data), Quantity: t.quantity, Open: true, Position: strategy.Long}
+	case d == strategy.Sell && t.direction.Allows(strategy.Short):
+		return &Fill{Side: deals.Sell, Price: SellPrice(data), Quantity: t.quantity, Open: true, Position: strategy.Short}
+	}
+
+	return nil
+}
+
+// Risk обновляет пик капитала по котировке и проверяет лимиты убытка и просадки.
+// Возвращает нарушенный лимит или пустую строку
+func (t *Trader) Risk(data *fintech.PriceResponse) string {
+	equity := t.FactYield.Add(t.Unrealized(data))
+
+	if equity.GreaterThan(t.PeakEquity) {
+		t.PeakEquity = equity
+	}
+
+	if t.maxLoss.IsPositive() && equity.LessThanOrEqual(t.maxLoss.Neg()) {
+		return "max loss"
+	}
+
+	if t.maxDD.IsPositive() && t.PeakEquity.Sub(equity).GreaterThanOrEqual(t.maxDD) {
+		return "max drawdown"
+	}
+
+	return ""
+}
+
+// Plan действие робота, если FactYield достиг плановой доходности, и пустое действие, если нет
+// или действие уже выполнено. Для PlanClose возвращает и заявку на закрытие открытой позиции
+func (t *Trader) Plan(data *fintech.PriceResponse) (robots.PlanAction, *Fill) {
+	if t.planDone || !t.planYield.IsPositive() || t.FactYield.LessThan(t.planYield) {
+		return "", nil
+	}
+
+	action := t.onPlan
+	if action == "" {
+		action = robots.PlanNotify
+	}
+
+	if action == robots.PlanClose {
+		return action, t.Close(data, deals.ExitPlanYield)
+	}
+
+	return action, nil
+}
+
+// PlanDone отмечает, что действие по плановой доходности выполнено и больше не повторяется
+func (t *Trader) PlanDone() {
+	t.planDone = true
+}
+
+// Close заявка на закрытие открытой позиции по котировке, nil если позиции нет.
+// Long закрывается продажей, short откупается покупкой
+func (t *Trader) Close(data *fintech.PriceResponse, reason string) *Fill {
+	switch t.Side {
+	case strategy.Long:
+		return &Fill{Side: deals.Sell, Price: SellPrice(data), Quantity: t.Position, Reason: reason, Position: t.Side}
+	case strategy.Short:
+		return &Fill{Side: deals.Buy, Price: BuyPrice(data), Quantity: t.Position, Reason: reason, Position: t.Side}
+	case strategy.Flat:
+	}
+
+	return nil
+}
+
+// exit проверяет стоп-лосс, трейлинг-стоп и тейк-профит открытой позиции по цене, по которой она закроется
+func (t *Trader) exit(data *fintech.PriceResponse) string {
+	switch t.Side {
+	case strategy.Long:
+		price := SellPrice(data)
+
+		if price.GreaterThan(t.HighWater) {
+			t.HighWater = price
+		}
+
+		if t.stopLoss.Enabled() && price.LessThanOrEqual(t.EntryPrice.Sub(t.stopLoss.Offset(t.EntryPrice))) {
+			return deals.ExitStopLoss
+		}
+
+		if t.trailing.Enabled() && price.LessThanOrEqual(t.HighWater.Sub(t.trailing.Offset(t.HighWater))) {
+			return deals.ExitTrailing
+		}
+
+		if t.takeProfit.Enabled() && price.GreaterThanOrEqual(t.EntryPrice.Add(t.takeProfit.Offset(t.EntryPrice))) {
+			return deals.ExitTakeProfit
+		}
+	case strategy.Short:
+		price := BuyPrice(data)
+
+		if t.HighWater.IsZero() || price.LessThan(t.HighWater) {
+			t.HighWater = price
+		}
+
+		if t.stopLoss.Enabled() && price.GreaterThanOrEqual(t.EntryPrice.Add(t.stopLoss.Offset(t.EntryPrice))) {
+			return deals.ExitStopLoss
+		}
+
+		if t.trailing.Enabled() && price.GreaterThanOrEqual(t.HighWater.Add(t.trailing.Offset(t.HighWater))) {
+			return deals.ExitTrailing
+		}
+
+		if t.takeProfit.Enabled() && price.LessThanOrEqual(t.EntryPrice.Sub(t.takeProfit.Offset(t.EntryPrice))) {
+			return deals.ExitTakeProfit
+		}
+	case strategy.Flat:
+	}
+
+	return ""
+}
+
+// Execute применяет исполненную сделку к позиции и счетчикам
+func (t *Trader) Execute(fill *Fill, now time.Time) {
+	if fill.Open {
+		t.Side = fill.Position
+		t.EntryPrice = fill.Price
+		t.Position = fill.Quantity
+		t.HighWater = decimal.Zero
+		t.EntryTime = now
+	} else {
+		t.DealsCount++
+		t.FactYield = t.FactYield.Add(profit(t.Side, t.EntryPrice, fill.Price, fill.Quantity))
+		t.Side = strategy.Flat
+		t.EntryPrice = decimal.Zero
+		t.Position = 0
+		t.HighWater = decimal.Zero
+		t.EntryTime = time.Time{}
+	}
+}
+
+// Unrealized нереализованный результат открытой позиции по котировке
+func (t *Trader) Unrealized(data *fintech.PriceResponse) decimal.Decimal {
+	switch t.Side {
+	case strategy.Long:
+		return profit(t.Side, t.EntryPrice, SellPrice(data), t.Position)
+	case strategy.Short:
+		return profit(t.Side, t.EntryPrice, BuyPrice(data), t.Position)
+	case strategy.Flat:
+	}
+
+	return decimal.Zero
+}
+
+// profit результат закрытия позиции side по цене exit: long зарабатывает на росте, short на падении
+func profit(side strategy.Side, entry, exit decimal.Decimal, quantity int) decimal.Decimal {
+	diff := exit.Sub(entry)
+	if side == strategy.Short {
+		diff = diff.Neg()
+	}
+
+	return diff.Mul(decimal.NewFromInt(int64(quantity)))
+}
+
+// BuyPrice цена покупки котировки без двоичных хвостов float64
+func BuyPrice(data *fintech.PriceResponse) decimal.Decimal {
+	return decimal.NewFromFloat(data.GetBuyPrice())
+}
+
+// SellPrice цена продажи котировки
+func SellPrice(data *fintech.PriceResponse) decimal.Decimal {
+	return decimal.NewFromFloat(data.GetSellPrice())
+}
+
+// QuoteTime время котировки или нулевое время, если биржа его не прислала
+func QuoteTime(data *fintech.PriceResponse) time.Time {
+	if data.GetTs() == nil {
+		return time.Time{}
+	}
+
+	ts, err := ptypes.Timestamp(data.GetTs())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return ts
+}
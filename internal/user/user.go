@@ -24,7 +24,9 @@ type User struct {
 	LastName  string    `json:"last_name"`
 	Birthday  string    `json:"birthday,omitempty"`
 	Email     string    `json:"email"`
+	Timezone  string    `json:"timezone,omitempty"`
 	Password  string    `json:"pass"`
+	IsAdmin   bool      `json:"-"`
 	UpdatedAt time.Time `json:"-"`
 	CreatedAt time.Time `json:"-"`
 }
@@ -35,6 +37,7 @@ type ForUpdate struct {
 	LastName  string `json:"last_name"`
 	Birthday  string `json:"birthday,omitempty"`
 	Email     string `json:"email"`
+	Timezone  string `json:"timezone,omitempty"`
 }
 
 //HashPass ...
@@ -75,6 +78,10 @@ func CheckValidUser(user *User) error {
 		return err
 	}
 
+	if _, err := time.LoadLocation(user.Timezone); err != nil {
+		return errors.New("unknown timezone")
+	}
+
 	return nil
 }
 
@@ -100,6 +107,17 @@ func FormForUpdate(user User) ForUpdate {
 	u.Email = user.Email
 	u.FirstName = user.FirstName
 	u.LastName = user.LastName
+	u.Timezone = user.Timezone
 
 	return u
 }
+
+// Location временная зона пользователя, в ней показываются и вводятся времена; по умолчанию UTC
+func (u *User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
@@ -0,0 +1,3 @@
+ALTER TABLE public.robots
+    ADD COLUMN strategy text NOT NULL DEFAULT 'threshold',
+    ADD COLUMN strategy_params jsonb NOT NULL DEFAULT '{}';
@@ -0,0 +1,12 @@
+CREATE TABLE public.deals (
+    id bigserial PRIMARY KEY,
+    robot_id integer NOT NULL,
+    side text NOT NULL,
+    price numeric(5, 2) NOT NULL,
+    quantity integer NOT NULL,
+    quote_ts timestamp,
+    executed_at timestamp NOT NULL,
+    FOREIGN KEY (robot_id) REFERENCES public.robots(id)
+);
+
+CREATE INDEX deals_robot_id_idx ON public.deals (robot_id);
@@ -0,0 +1,2 @@
+ALTER TABLE public.robots
+    ADD COLUMN is_degraded boolean NOT NULL DEFAULT false;
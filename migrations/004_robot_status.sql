@@ -0,0 +1,11 @@
+ALTER TABLE public.robots
+    ADD COLUMN status text NOT NULL DEFAULT 'draft',
+    ADD COLUMN status_reason text NOT NULL DEFAULT '',
+    ADD COLUMN status_changed_at timestamp;
+
+UPDATE public.robots SET status = CASE
+        WHEN deleted_at IS NOT NULL THEN 'deleted'
+        WHEN is_active THEN 'scheduled'
+        ELSE 'draft'
+    END,
+    status_changed_at = now();
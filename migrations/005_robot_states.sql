@@ -0,0 +1,9 @@
+CREATE TABLE public.robot_states (
+    robot_id integer PRIMARY KEY,
+    side text NOT NULL,
+    entry_price numeric(5, 2) NOT NULL,
+    entry_time timestamp,
+    last_tick_ts timestamp,
+    updated_at timestamp NOT NULL,
+    FOREIGN KEY (robot_id) REFERENCES public.robots(id)
+);
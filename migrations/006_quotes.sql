@@ -0,0 +1,9 @@
+-- партиции по дням создает сам recorder, см. internal/postgres/quotes_pgx.go
+CREATE TABLE public.quotes (
+    ticker text NOT NULL,
+    buy_price numeric(5, 2) NOT NULL,
+    sell_price numeric(5, 2) NOT NULL,
+    ts timestamp NOT NULL
+) PARTITION BY RANGE (ts);
+
+CREATE INDEX quotes_ticker_ts_idx ON public.quotes (ticker, ts);
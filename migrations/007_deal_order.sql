@@ -0,0 +1 @@
+ALTER TABLE public.deals ADD COLUMN order_id text NOT NULL DEFAULT '';
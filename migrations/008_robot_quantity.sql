@@ -0,0 +1,6 @@
+ALTER TABLE public.robots ADD COLUMN quantity integer NOT NULL DEFAULT 1;
+
+ALTER TABLE public.robot_states ADD COLUMN quantity integer NOT NULL DEFAULT 0;
+
+-- позиции, открытые до появления размера сделки, были по одной бумаге
+UPDATE public.robot_states SET quantity = 1 WHERE side = 'long';
@@ -0,0 +1,9 @@
+ALTER TABLE public.robots
+    ADD COLUMN stop_loss numeric(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN stop_loss_percent boolean NOT NULL DEFAULT false,
+    ADD COLUMN take_profit numeric(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN take_profit_percent boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.deals ADD COLUMN exit_reason text NOT NULL DEFAULT '';
+
+UPDATE public.deals SET exit_reason = 'signal' WHERE side = 'sell';
@@ -0,0 +1,5 @@
+ALTER TABLE public.robots
+    ADD COLUMN trailing_stop numeric(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN trailing_stop_percent boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.robot_states ADD COLUMN high_water numeric(5, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE public.robots
+    ADD COLUMN on_plan_yield text NOT NULL DEFAULT 'notify',
+    ADD COLUMN plan_outcome text NOT NULL DEFAULT '',
+    ADD COLUMN plan_reached_at timestamp;
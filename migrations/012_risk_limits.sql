@@ -0,0 +1,8 @@
+ALTER TABLE public.robots
+    ADD COLUMN max_loss numeric(5, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN max_drawdown numeric(5, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE public.robot_states ADD COLUMN peak_equity numeric(5, 2) NOT NULL DEFAULT 0;
+
+UPDATE public.robot_states st SET peak_equity = GREATEST(r.fact_yield, 0)
+FROM public.robots r WHERE r.id = st.robot_id;
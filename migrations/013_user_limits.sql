@@ -0,0 +1,11 @@
+ALTER TABLE public.users ADD COLUMN is_admin boolean NOT NULL DEFAULT false;
+
+CREATE TABLE public.user_limits (
+    user_id integer PRIMARY KEY,
+    max_active_robots integer NOT NULL DEFAULT 0,
+    max_ticker_exposure numeric(12, 2) NOT NULL DEFAULT 0,
+    max_total_exposure numeric(12, 2) NOT NULL DEFAULT 0,
+    max_deals_per_day integer NOT NULL DEFAULT 0,
+    updated_at timestamp NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
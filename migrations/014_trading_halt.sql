@@ -0,0 +1,7 @@
+CREATE TABLE public.trading_halt (
+    id integer PRIMARY KEY CHECK (id = 1),
+    halted boolean NOT NULL,
+    flatten boolean NOT NULL,
+    reason text NOT NULL,
+    changed_at timestamp NOT NULL
+);
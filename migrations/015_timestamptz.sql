@@ -0,0 +1,81 @@
+-- До перехода на timestamptz и приложение, и база писали время по Москве без зоны
+-- (отсюда +3 часа в сравнениях), а время котировок и исполнений приходило с биржи в UTC.
+-- Если база работала в другой зоне, поправьте 'Europe/Moscow' ниже перед запуском.
+BEGIN;
+
+ALTER TABLE public.users
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'Europe/Moscow',
+    ADD COLUMN timezone text NOT NULL DEFAULT 'Europe/Moscow';
+
+ALTER TABLE public.users ALTER COLUMN timezone SET DEFAULT 'UTC';
+
+ALTER TABLE public.sessions
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN valid_until TYPE timestamptz USING valid_until AT TIME ZONE 'Europe/Moscow';
+
+ALTER TABLE public.robots
+    ALTER COLUMN plan_start TYPE timestamptz USING plan_start AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN plan_end TYPE timestamptz USING plan_end AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN activated_at TYPE timestamptz USING activated_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN deactivated_at TYPE timestamptz USING deactivated_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN created_at TYPE timestamptz USING created_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN deleted_at TYPE timestamptz USING deleted_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN status_changed_at TYPE timestamptz USING status_changed_at AT TIME ZONE 'Europe/Moscow',
+    ALTER COLUMN plan_reached_at TYPE timestamptz USING plan_reached_at AT TIME ZONE 'Europe/Moscow';
+
+ALTER TABLE public.deals
+    ALTER COLUMN quote_ts TYPE timestamptz USING quote_ts AT TIME ZONE 'UTC',
+    ALTER COLUMN executed_at TYPE timestamptz USING executed_at AT TIME ZONE 'UTC';
+
+ALTER TABLE public.robot_states
+    ALTER COLUMN entry_time TYPE timestamptz USING entry_time AT TIME ZONE 'UTC',
+    ALTER COLUMN last_tick_ts TYPE timestamptz USING last_tick_ts AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'Europe/Moscow';
+
+ALTER TABLE public.user_limits
+    ALTER COLUMN updated_at TYPE timestamptz USING updated_at AT TIME ZONE 'Europe/Moscow';
+
+ALTER TABLE public.trading_halt
+    ALTER COLUMN changed_at TYPE timestamptz USING changed_at AT TIME ZONE 'Europe/Moscow';
+
+-- ключ партиционирования нельзя сменить на месте: переносим котировки в новую таблицу,
+-- дневные партиции по UTC создаются заново
+ALTER TABLE public.quotes RENAME TO quotes_legacy;
+ALTER INDEX public.quotes_ticker_ts_idx RENAME TO quotes_legacy_ticker_ts_idx;
+
+CREATE TABLE public.quotes (
+    ticker text NOT NULL,
+    buy_price numeric(5, 2) NOT NULL,
+    sell_price numeric(5, 2) NOT NULL,
+    ts timestamptz NOT NULL
+) PARTITION BY RANGE (ts);
+
+CREATE INDEX quotes_ticker_ts_idx ON public.quotes (ticker, ts);
+
+DO $$
+DECLARE
+    d date;
+    part text;
+BEGIN
+    -- переименовываем все старые партиции, а не только дни с котировками: неудачная пачка
+    -- могла оставить пустую партицию, и ее имя заняло бы имя новой
+    FOR part IN SELECT c.relname FROM pg_inherits i
+        JOIN pg_class c ON c.oid = i.inhrelid JOIN pg_class p ON p.oid = i.inhparent
+        WHERE p.relname = 'quotes_legacy' AND c.relname LIKE 'quotes\_%' LOOP
+        EXECUTE format('ALTER TABLE public.%I RENAME TO %I', part, 'quotes_legacy_' || substr(part, 8));
+    END LOOP;
+
+    FOR d IN SELECT DISTINCT ts::date FROM public.quotes_legacy LOOP
+        part := 'quotes_' || to_char(d, 'YYYYMMDD');
+        EXECUTE format('CREATE TABLE public.%I PARTITION OF public.quotes FOR VALUES FROM (%L) TO (%L)',
+            part, d::text || ' 00:00:00+00', (d + 1)::text || ' 00:00:00+00');
+    END LOOP;
+END $$;
+
+INSERT INTO public.quotes (ticker, buy_price, sell_price, ts)
+SELECT ticker, buy_price, sell_price, ts AT TIME ZONE 'UTC' FROM public.quotes_legacy;
+
+DROP TABLE public.quotes_legacy CASCADE;
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- деньги хранятся с запасом: numeric(5, 2) ограничивал цены 999.99,
+-- а доходности были целыми и округлялись при записи
+UPDATE public.robots SET fact_yield = 0 WHERE fact_yield IS NULL;
+
+ALTER TABLE public.robots
+    ALTER COLUMN buy_price TYPE numeric(18, 4),
+    ALTER COLUMN sell_price TYPE numeric(18, 4),
+    ALTER COLUMN plan_yield TYPE numeric(18, 4),
+    ALTER COLUMN fact_yield TYPE numeric(18, 4),
+    ALTER COLUMN fact_yield SET DEFAULT 0,
+    ALTER COLUMN fact_yield SET NOT NULL,
+    ALTER COLUMN stop_loss TYPE numeric(18, 4),
+    ALTER COLUMN take_profit TYPE numeric(18, 4),
+    ALTER COLUMN trailing_stop TYPE numeric(18, 4),
+    ALTER COLUMN max_loss TYPE numeric(18, 4),
+    ALTER COLUMN max_drawdown TYPE numeric(18, 4);
+
+ALTER TABLE public.deals ALTER COLUMN price TYPE numeric(18, 4);
+
+ALTER TABLE public.robot_states
+    ALTER COLUMN entry_price TYPE numeric(18, 4),
+    ALTER COLUMN high_water TYPE numeric(18, 4),
+    ALTER COLUMN peak_equity TYPE numeric(18, 4);
+
+ALTER TABLE public.quotes
+    ALTER COLUMN buy_price TYPE numeric(18, 4),
+    ALTER COLUMN sell_price TYPE numeric(18, 4);
+
+ALTER TABLE public.user_limits
+    ALTER COLUMN max_ticker_exposure TYPE numeric(18, 4),
+    ALTER COLUMN max_total_exposure TYPE numeric(18, 4);
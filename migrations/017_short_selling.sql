@@ -0,0 +1,6 @@
+-- существующие роботы становятся long без проверки порогов: у части из них BuyPrice не ниже SellPrice,
+-- поэтому CheckDirection при обновлении робота запускается, только если меняется направление или пороги
+ALTER TABLE public.robots ADD COLUMN direction text NOT NULL DEFAULT 'long';
+
+-- сторона позиции, которую сделка открывает или закрывает; до шортов все сделки были long
+ALTER TABLE public.deals ADD COLUMN position text NOT NULL DEFAULT 'long';
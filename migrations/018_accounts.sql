@@ -0,0 +1,35 @@
+CREATE TABLE public.accounts (
+    user_id integer PRIMARY KEY,
+    cash numeric(18, 4) NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+CREATE TABLE public.holdings (
+    user_id integer NOT NULL,
+    ticker text NOT NULL,
+    quantity integer NOT NULL DEFAULT 0,
+    avg_price numeric(18, 4) NOT NULL DEFAULT 0,
+    realized_pnl numeric(18, 4) NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL,
+    PRIMARY KEY (user_id, ticker),
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+-- журнал только дополняется, баланс счета равен сумме amount
+CREATE TABLE public.transactions (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL,
+    kind text NOT NULL,
+    ticker text NOT NULL DEFAULT '',
+    quantity integer NOT NULL DEFAULT 0,
+    price numeric(18, 4) NOT NULL DEFAULT 0,
+    amount numeric(18, 4) NOT NULL,
+    deal_id integer NOT NULL DEFAULT 0,
+    robot_id integer NOT NULL DEFAULT 0,
+    note text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES public.users(id)
+);
+
+CREATE INDEX transactions_user_id_idx ON public.transactions (user_id);
@@ -0,0 +1,4 @@
+-- пустое расписание означает разовое окно plan_start-plan_end, так работают все существующие роботы
+ALTER TABLE public.robots
+    ADD COLUMN schedule text NOT NULL DEFAULT '',
+    ADD COLUMN schedule_exclude text[] NOT NULL DEFAULT '{}';
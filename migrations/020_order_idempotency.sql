@@ -0,0 +1,5 @@
+-- order_seq дает заявкам робота постоянный ClientOrderID, pending_order хранит заявку до записи сделки,
+-- чтобы после сбоя записи или рестарта повторить ее и получить от биржи то же исполнение
+ALTER TABLE public.robot_states
+    ADD COLUMN order_seq integer NOT NULL DEFAULT 0,
+    ADD COLUMN pending_order jsonb;
@@ -0,0 +1,31 @@
+-- пользователи, торговавшие до появления счетов, никогда не пополняли счет, и проверка денег запретила бы
+-- их роботам любой вход. Таким пользователям зачисляется начальный депозит: сколько нужно на вход всем их
+-- неудаленным роботам по порогам (как robots.Robot.EntryAmount) плюс покрытие уже ушедшего в минус баланса.
+-- Депозит пишется в журнал, так что баланс по-прежнему равен сумме amount
+BEGIN;
+
+INSERT INTO public.accounts (user_id, cash, updated_at)
+SELECT u.id, 0, now() FROM public.users u
+WHERE NOT EXISTS (SELECT 1 FROM public.accounts a WHERE a.user_id = u.id);
+
+INSERT INTO public.transactions (user_id, kind, amount, note, created_at)
+SELECT o.user_id, 'deposit', o.amount, 'opening balance for robots created before accounts', now()
+FROM (
+    SELECT a.user_id, greatest(-a.cash, 0) + coalesce((
+        SELECT sum(CASE r.direction
+                WHEN 'long' THEN r.buy_price
+                WHEN 'short' THEN r.sell_price
+                ELSE greatest(r.buy_price, r.sell_price)
+            END * r.quantity)
+        FROM public.robots r WHERE r.owner_user_id = a.user_id AND r.deleted_at IS NULL
+    ), 0) AS amount
+    FROM public.accounts a
+    WHERE NOT EXISTS (SELECT 1 FROM public.transactions t WHERE t.user_id = a.user_id AND t.kind = 'deposit')
+) o
+WHERE o.amount > 0;
+
+UPDATE public.accounts a SET cash = a.cash + t.amount, updated_at = now()
+FROM public.transactions t
+WHERE t.user_id = a.user_id AND t.kind = 'deposit' AND t.note = 'opening balance for robots created before accounts';
+
+COMMIT;
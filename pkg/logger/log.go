@@ -83,6 +83,11 @@ func newZapLogger(config Configuration) (Logger, error) {
 	}, nil
 }
 
+// NewNop returns a logger that discards everything, useful in tests
+func NewNop() Logger {
+	return &zapLogger{zap.NewNop().Sugar()}
+}
+
 func (l *zapLogger) Debugf(format string, args ...interface{}) {
 	l.sugaredLogger.Debugf(format, args...)
 }